@@ -0,0 +1,13 @@
+resource "m3ter_account" "test" {
+  name          = "terraform test"
+  code          = "terraform_test"
+  email_address = "billing@example.com"
+  address = {
+    address_line1 = "1 Main Street"
+    locality      = "Springfield"
+    region        = "IL"
+    post_code     = "62701"
+    country       = "US"
+  }
+  custom_fields = {}
+}
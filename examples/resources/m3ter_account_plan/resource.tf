@@ -0,0 +1,6 @@
+resource "m3ter_account_plan" "test" {
+  account_id    = m3ter_account.test.id
+  plan_id       = m3ter_plan.test.id
+  start_date    = "2025-01-01"
+  custom_fields = {}
+}
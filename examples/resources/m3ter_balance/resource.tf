@@ -0,0 +1,10 @@
+resource "m3ter_balance" "test" {
+  account_id                = m3ter_account.test.id
+  name                      = "Prepayment"
+  currency                  = "USD"
+  amount                    = 5000
+  start_date                = "2025-01-01"
+  end_date                  = "2026-01-01"
+  overage_surcharge_percent = 10
+  custom_fields             = {}
+}
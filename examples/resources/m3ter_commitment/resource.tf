@@ -0,0 +1,19 @@
+resource "m3ter_commitment" "test" {
+  account_id = m3ter_account.test.id
+  amount     = 12000
+  currency   = "USD"
+  start_date = "2025-01-01"
+  end_date   = "2026-01-01"
+  fee_dates = [
+    {
+      date   = "2025-01-01"
+      amount = 6000
+    },
+    {
+      date   = "2025-07-01"
+      amount = 6000
+    },
+  ]
+  line_item_types = ["STANDING_CHARGE", "USAGE"]
+  custom_fields   = {}
+}
@@ -0,0 +1,4 @@
+resource "m3ter_credit_reason" "test" {
+  name = "Service outage"
+  code = "service_outage"
+}
@@ -0,0 +1,6 @@
+resource "m3ter_currency" "test" {
+  name               = "EUR"
+  code               = "EUR"
+  max_decimal_places = 2
+  rounding_mode      = "HALF_UP"
+}
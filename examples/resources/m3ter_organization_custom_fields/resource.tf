@@ -0,0 +1,11 @@
+resource "m3ter_organization_custom_fields" "custom_fields" {
+  custom_fields = {
+    account = {
+      salesforce_id = ""
+      tier          = 1
+    }
+    meter = {
+      team = "billing"
+    }
+  }
+}
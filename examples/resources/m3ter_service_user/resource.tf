@@ -0,0 +1,4 @@
+resource "m3ter_service_user" "test" {
+  name                  = "terraform-onboarding"
+  permission_policy_ids = ["00000000-0000-0000-0000-000000000000"]
+}
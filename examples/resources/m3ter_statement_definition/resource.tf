@@ -0,0 +1,16 @@
+resource "m3ter_statement_definition" "test" {
+  name                  = "Daily usage"
+  include_pricing_type  = "BOTH"
+  aggregation_frequency = "DAY"
+
+  dimensions = [{
+    name     = "region"
+    meter_id = m3ter_meter.test.id
+    filter   = ["*"]
+  }]
+
+  measures = [{
+    meter_id     = m3ter_meter.test.id
+    aggregations = ["SUM"]
+  }]
+}
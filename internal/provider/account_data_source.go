@@ -0,0 +1,118 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AccountDataSource{}
+
+func NewAccountDataSource() datasource.DataSource {
+	r := &AccountDataSource{}
+	r.genericDataSource = genericDataSource[AccountDataSourceModel, *AccountDataSourceModel]{
+		typeName: "account",
+		path:     "/accounts",
+		name:     "account",
+		filters: func(data *AccountDataSourceModel) map[string]types.String {
+			return map[string]types.String{
+				"code":         data.Code,
+				"emailAddress": data.EmailAddress,
+			}
+		},
+		extraQuery: func(data *AccountDataSourceModel) types.Map {
+			return data.ExtraQuery
+		},
+		read: r.read,
+	}
+	return r
+}
+
+// AccountDataSource defines the data source implementation.
+type AccountDataSource struct {
+	genericDataSource[AccountDataSourceModel, *AccountDataSourceModel]
+}
+
+type AccountDataSourceModel struct {
+	Code            types.String  `tfsdk:"code"`
+	EmailAddress    types.String  `tfsdk:"email_address"`
+	Name            types.String  `tfsdk:"name"`
+	Currency        types.String  `tfsdk:"currency"`
+	ParentAccountId types.String  `tfsdk:"parent_account_id"`
+	CustomFields    types.Dynamic `tfsdk:"custom_fields"`
+	Id              types.String  `tfsdk:"id"`
+	Version         types.Int64   `tfsdk:"version"`
+	ExtraQuery      types.Map     `tfsdk:"extra_query"`
+}
+
+func (r *AccountDataSourceModel) GetId() types.String {
+	return r.Id
+}
+
+func (r *AccountDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Account data source",
+
+		Attributes: map[string]schema.Attribute{
+			"code": schema.StringAttribute{
+				MarkdownDescription: "Code of the Account. This is a unique short code used for the Account.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"email_address": schema.StringAttribute{
+				MarkdownDescription: "Contact email address for the Account.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the Account.",
+				Computed:            true,
+			},
+			"currency": schema.StringAttribute{
+				MarkdownDescription: "Account level billing currency, such as USD or GBP, if set.",
+				Computed:            true,
+			},
+			"parent_account_id": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the parent Account, for Accounts in a billing hierarchy.",
+				Computed:            true,
+			},
+			"custom_fields": schema.DynamicAttribute{
+				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be a string, a number or a boolean.",
+				Computed:            true,
+			},
+			"id": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The UUID of the entity.",
+			},
+			"version": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The version number.",
+			},
+			"extra_query": extraQueryAttribute,
+		},
+	}
+}
+
+func (r *AccountDataSource) read(ctx context.Context, data *AccountDataSourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           restData,
+	}
+	m.to("id", &data.Id)
+	m.to("version", &data.Version)
+	m.to("code", &data.Code)
+	m.to("emailAddress", &data.EmailAddress)
+	m.to("name", &data.Name)
+	m.to("currency", &data.Currency)
+	m.to("parentAccountId", &data.ParentAccountId)
+	m.customFieldsTo(&data.CustomFields)
+}
@@ -0,0 +1,187 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AccountPlanResource{}
+var _ resource.ResourceWithImportState = &AccountPlanResource{}
+var _ resource.ResourceWithModifyPlan = &AccountPlanResource{}
+var _ resource.ResourceWithConfigValidators = &AccountPlanResource{}
+
+func NewAccountPlanResource() resource.Resource {
+	r := &AccountPlanResource{}
+	r.genericResource = genericResource[AccountPlanResourceModel, *AccountPlanResourceModel]{
+		typeName: "account_plan",
+		path:     "/accountplans",
+		name:     "account plan",
+		read:     r.read,
+		write:    r.write,
+	}
+	return r
+}
+
+// AccountPlanResource defines the resource implementation.
+type AccountPlanResource struct {
+	genericResource[AccountPlanResourceModel, *AccountPlanResourceModel]
+}
+
+// AccountPlanResourceModel describes the resource data model.
+type AccountPlanResourceModel struct {
+	AccountId        types.String   `tfsdk:"account_id"`
+	PlanId           types.String   `tfsdk:"plan_id"`
+	PlanGroupId      types.String   `tfsdk:"plan_group_id"`
+	StartDate        types.String   `tfsdk:"start_date"`
+	EndDate          types.String   `tfsdk:"end_date"`
+	BillEpoch        types.String   `tfsdk:"bill_epoch"`
+	ChildBillingMode types.String   `tfsdk:"child_billing_mode"`
+	CustomFields     types.Dynamic  `tfsdk:"custom_fields"`
+	Id               types.String   `tfsdk:"id"`
+	Version          types.Int64    `tfsdk:"version"`
+	Timeouts         timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *AccountPlanResourceModel) GetId() types.String {
+	return r.Id
+}
+
+func (r *AccountPlanResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "AccountPlan resource. Attaches a Plan or a Plan Group to an Account.",
+
+		Attributes: map[string]schema.Attribute{
+			"account_id": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the Account the Plan or Plan Group is attached to.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"plan_id": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the Plan attached to the Account. Exactly one of `plan_id` and `plan_group_id` must be set.",
+				Optional:            true,
+			},
+			"plan_group_id": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the Plan Group attached to the Account. Exactly one of `plan_id` and `plan_group_id` must be set.",
+				Optional:            true,
+			},
+			"start_date": schema.StringAttribute{
+				MarkdownDescription: "The date (in ISO-8601 format) from which the Plan or Plan Group applies to the Account.",
+				Required:            true,
+			},
+			"end_date": schema.StringAttribute{
+				MarkdownDescription: "The date (in ISO-8601 format) after which the Plan or Plan Group no longer applies to the Account.",
+				Optional:            true,
+			},
+			"bill_epoch": schema.StringAttribute{
+				MarkdownDescription: "The starting date (in ISO-8601 format) from which the billing cycles are calculated.",
+				Optional:            true,
+			},
+			"child_billing_mode": schema.StringAttribute{
+				MarkdownDescription: "How the billing of a child Account is handled in an Account hierarchy. One of PARENT_SUMMARY, PARENT_BREAKDOWN or CHILD.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf("PARENT_SUMMARY", "PARENT_BREAKDOWN", "CHILD"),
+				},
+			},
+			"custom_fields": schema.DynamicAttribute{
+				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be a string, a number or a boolean.",
+				Required:            true,
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The UUID of the entity.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"version": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The version number.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
+	}
+}
+
+func (r *AccountPlanResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("plan_id"),
+			path.MatchRoot("plan_group_id"),
+		),
+	}
+}
+
+func (r *AccountPlanResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkRequiredCustomFields(ctx, r.client, req.Plan, &resp.Diagnostics)
+}
+
+func (r *AccountPlanResource) read(ctx context.Context, data *AccountPlanResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           restData,
+	}
+	m.to("id", &data.Id)
+	m.to("version", &data.Version)
+	m.to("accountId", &data.AccountId)
+	m.to("planId", &data.PlanId)
+	m.to("planGroupId", &data.PlanGroupId)
+	m.to("startDate", &data.StartDate)
+	m.to("endDate", &data.EndDate)
+	m.to("billEpoch", &data.BillEpoch)
+	m.to("childBillingMode", &data.ChildBillingMode)
+	if data.ChildBillingMode.IsUnknown() {
+		data.ChildBillingMode = types.StringNull()
+	}
+	m.customFieldsTo(&data.CustomFields)
+}
+
+func (r *AccountPlanResource) write(ctx context.Context, data *AccountPlanResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           restData,
+	}
+	m.from(data.Id, "id")
+	m.from(data.Version, "version")
+	m.from(data.AccountId, "accountId")
+	m.from(data.PlanId, "planId")
+	m.from(data.PlanGroupId, "planGroupId")
+	// The body starts from the current entity, so the key of whichever of the
+	// plan and plan group is no longer attached must be removed
+	if data.PlanId.IsNull() {
+		delete(m.v, "planId")
+	}
+	if data.PlanGroupId.IsNull() {
+		delete(m.v, "planGroupId")
+	}
+	m.from(data.StartDate, "startDate")
+	m.from(data.EndDate, "endDate")
+	m.from(data.BillEpoch, "billEpoch")
+	m.from(data.ChildBillingMode, "childBillingMode")
+	m.customFieldsFrom(data.CustomFields)
+}
@@ -0,0 +1,42 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+)
+
+func TestAccountPlanResourceSwitchPlanAndPlanGroup(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	config := map[string]any{
+		"account_id":    "00000000-0000-4000-8000-0000000000a1",
+		"plan_id":       "00000000-0000-4000-8000-0000000000b1",
+		"start_date":    "2024-01-01",
+		"custom_fields": map[string]any{},
+	}
+	state := p.create("m3ter_account_plan", config)
+	id := attrValue(t, state, "id").(string)
+
+	// Moving the account to a plan group stops sending the plan
+	delete(config, "plan_id")
+	config["plan_group_id"] = "00000000-0000-4000-8000-0000000000c1"
+	state = p.update("m3ter_account_plan", state, config)
+	sent := api.get("accountplans", id)
+	if _, ok := sent["planId"]; ok || sent["planGroupId"] != config["plan_group_id"] {
+		t.Errorf("sent planId %v and planGroupId %v, want only the plan group", sent["planId"], sent["planGroupId"])
+	}
+	p.assertNoChanges("m3ter_account_plan", state, config)
+
+	// And back again
+	delete(config, "plan_group_id")
+	config["plan_id"] = "00000000-0000-4000-8000-0000000000b2"
+	state = p.update("m3ter_account_plan", state, config)
+	sent = api.get("accountplans", id)
+	if _, ok := sent["planGroupId"]; ok || sent["planId"] != config["plan_id"] {
+		t.Errorf("sent planId %v and planGroupId %v, want only the plan", sent["planId"], sent["planGroupId"])
+	}
+	p.assertNoChanges("m3ter_account_plan", state, config)
+}
@@ -0,0 +1,485 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AccountResource{}
+var _ resource.ResourceWithImportState = &AccountResource{}
+var _ resource.ResourceWithModifyPlan = &AccountResource{}
+
+func NewAccountResource() resource.Resource {
+	r := &AccountResource{}
+	r.genericResource = genericResource[AccountResourceModel, *AccountResourceModel]{
+		typeName:     "account",
+		path:         "/accounts",
+		name:         "account",
+		importFields: []string{"code", "emailAddress"},
+		read:         r.read,
+		write:        r.write,
+	}
+	return r
+}
+
+// AccountResource defines the resource implementation.
+type AccountResource struct {
+	genericResource[AccountResourceModel, *AccountResourceModel]
+}
+
+// AccountResourceModel describes the resource data model.
+type AccountResourceModel struct {
+	Name                      types.String   `tfsdk:"name"`
+	Code                      types.String   `tfsdk:"code"`
+	EmailAddress              types.String   `tfsdk:"email_address"`
+	Address                   types.Object   `tfsdk:"address"`
+	Currency                  types.String   `tfsdk:"currency"`
+	DaysBeforeBillDue         types.Int32    `tfsdk:"days_before_bill_due"`
+	PurchaseOrderNumber       types.String   `tfsdk:"purchase_order_number"`
+	ParentAccountId           types.String   `tfsdk:"parent_account_id"`
+	ChildBillingMode          types.String   `tfsdk:"child_billing_mode"`
+	ChildAccountIds           types.List     `tfsdk:"child_account_ids"`
+	CreditApplicationOrder    types.List     `tfsdk:"credit_application_order"`
+	AutoGenerateStatementMode types.String   `tfsdk:"auto_generate_statement_mode"`
+	CustomFields              types.Dynamic  `tfsdk:"custom_fields"`
+	Plans                     types.List     `tfsdk:"plans"`
+	Commitments               types.List     `tfsdk:"commitments"`
+	Id                        types.String   `tfsdk:"id"`
+	Version                   types.Int64    `tfsdk:"version"`
+	Timeouts                  timeouts.Value `tfsdk:"timeouts"`
+}
+
+// accountAddressFields maps the address attribute names to the API field names.
+var accountAddressFields = map[string]string{
+	"address_line1": "addressLine1",
+	"address_line2": "addressLine2",
+	"address_line3": "addressLine3",
+	"address_line4": "addressLine4",
+	"locality":      "locality",
+	"region":        "region",
+	"post_code":     "postCode",
+	"country":       "country",
+}
+
+var accountPlanEntryType = schema.NestedAttributeObject{
+	Attributes: map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			MarkdownDescription: "The UUID of the account plan.",
+			Computed:            true,
+		},
+		"plan_id": schema.StringAttribute{
+			MarkdownDescription: "The UUID of the attached Plan, if a Plan is attached.",
+			Computed:            true,
+		},
+		"plan_group_id": schema.StringAttribute{
+			MarkdownDescription: "The UUID of the attached Plan Group, if a Plan Group is attached.",
+			Computed:            true,
+		},
+		"start_date": schema.StringAttribute{
+			MarkdownDescription: "The date the attachment starts.",
+			Computed:            true,
+		},
+		"end_date": schema.StringAttribute{
+			MarkdownDescription: "The date the attachment ends, if any.",
+			Computed:            true,
+		},
+	},
+}
+
+// accountPlanEntryFields maps the plan entry attribute names to the API field names.
+var accountPlanEntryFields = map[string]string{
+	"id":            "id",
+	"plan_id":       "planId",
+	"plan_group_id": "planGroupId",
+	"start_date":    "startDate",
+	"end_date":      "endDate",
+}
+
+var accountCommitmentEntryType = schema.NestedAttributeObject{
+	Attributes: map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			MarkdownDescription: "The UUID of the Commitment.",
+			Computed:            true,
+		},
+		"amount": schema.Float64Attribute{
+			MarkdownDescription: "The committed amount.",
+			Computed:            true,
+		},
+		"currency": schema.StringAttribute{
+			MarkdownDescription: "The currency of the Commitment.",
+			Computed:            true,
+		},
+		"start_date": schema.StringAttribute{
+			MarkdownDescription: "The start date of the Commitment period.",
+			Computed:            true,
+		},
+		"end_date": schema.StringAttribute{
+			MarkdownDescription: "The end date of the Commitment period.",
+			Computed:            true,
+		},
+	},
+}
+
+// accountCommitmentEntryFields maps the commitment entry attribute names to the API field names.
+var accountCommitmentEntryFields = map[string]string{
+	"id":         "id",
+	"amount":     "amount",
+	"currency":   "currency",
+	"start_date": "startDate",
+	"end_date":   "endDate",
+}
+
+var accountAddressAttributes = map[string]schema.Attribute{
+	"address_line1": schema.StringAttribute{
+		MarkdownDescription: "First line of the address.",
+		Optional:            true,
+	},
+	"address_line2": schema.StringAttribute{
+		MarkdownDescription: "Second line of the address.",
+		Optional:            true,
+	},
+	"address_line3": schema.StringAttribute{
+		MarkdownDescription: "Third line of the address.",
+		Optional:            true,
+	},
+	"address_line4": schema.StringAttribute{
+		MarkdownDescription: "Fourth line of the address.",
+		Optional:            true,
+	},
+	"locality": schema.StringAttribute{
+		MarkdownDescription: "The locality, such as the city or town, of the address.",
+		Optional:            true,
+	},
+	"region": schema.StringAttribute{
+		MarkdownDescription: "The region, such as the state or county, of the address.",
+		Optional:            true,
+	},
+	"post_code": schema.StringAttribute{
+		MarkdownDescription: "The postal or zip code of the address.",
+		Optional:            true,
+	},
+	"country": schema.StringAttribute{
+		MarkdownDescription: "The country of the address.",
+		Optional:            true,
+	},
+}
+
+func (r *AccountResourceModel) GetId() types.String {
+	return r.Id
+}
+
+func (r *AccountResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Account resource",
+
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the Account.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(1, 200),
+				},
+			},
+			"code": schema.StringAttribute{
+				MarkdownDescription: "Code of the Account. This is a unique short code used for the Account.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(1, 80),
+					stringvalidator.RegexMatches(regexp.MustCompile(`^([^\p{Cc}\s])|([^\p{Cc}\s][[^\p{Cc}\s] ]*[^\p{Cc}\s])$`), "The code must not contain control characters or start/end with whitespace."),
+				},
+			},
+			"email_address": schema.StringAttribute{
+				MarkdownDescription: "Contact email address for the Account.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"address": schema.SingleNestedAttribute{
+				MarkdownDescription: "Contact address for the Account.",
+				Attributes:          accountAddressAttributes,
+				Optional:            true,
+			},
+			"currency": schema.StringAttribute{
+				MarkdownDescription: "Account level billing currency, such as USD or GBP. Optional attribute - if you do not define an Account level billing currency, then the Organization level billing currency is used.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(3, 3),
+				},
+			},
+			"days_before_bill_due": schema.Int32Attribute{
+				MarkdownDescription: "The number of days after the Bill generation date shown on Bills as the due date. Overrides the Organization level setting.",
+				Optional:            true,
+				Validators: []validator.Int32{
+					int32validator.AtLeast(1),
+				},
+			},
+			"purchase_order_number": schema.StringAttribute{
+				MarkdownDescription: "Purchase Order Number of the Account.",
+				Optional:            true,
+			},
+			"parent_account_id": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the parent Account, for Accounts in a billing hierarchy.",
+				Optional:            true,
+			},
+			"child_account_ids": schema.ListAttribute{
+				MarkdownDescription: "The UUIDs of the Accounts whose parent is this Account, in ascending order.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"child_billing_mode": schema.StringAttribute{
+				MarkdownDescription: "How the billing of a child Account is handled in an Account hierarchy. One of PARENT_SUMMARY, PARENT_BREAKDOWN or CHILD.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf("PARENT_SUMMARY", "PARENT_BREAKDOWN", "CHILD"),
+				},
+			},
+			"credit_application_order": schema.ListAttribute{
+				MarkdownDescription: "The order in which credits are applied to the Bills of the Account, from PREPAYMENT and BALANCE. Overrides the Organization level setting.",
+				Optional:            true,
+				ElementType:         types.StringType,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(
+						stringvalidator.OneOf("PREPAYMENT", "BALANCE"),
+					),
+				},
+			},
+			"auto_generate_statement_mode": schema.StringAttribute{
+				MarkdownDescription: "Whether and in which format statements are generated for the Bills of the Account. One of JSON_AND_CSV, JSON or NONE. Overrides the Organization level setting.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("JSON_AND_CSV", "JSON", "NONE"),
+				},
+			},
+			"custom_fields": schema.DynamicAttribute{
+				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be a string, a number or a boolean.",
+				Required:            true,
+			},
+			"plans": schema.ListNestedAttribute{
+				MarkdownDescription: "The Plans and Plan Groups attached to the Account.",
+				Computed:            true,
+				NestedObject:        accountPlanEntryType,
+			},
+			"commitments": schema.ListNestedAttribute{
+				MarkdownDescription: "The active Commitments of the Account.",
+				Computed:            true,
+				NestedObject:        accountCommitmentEntryType,
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The UUID of the entity.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"version": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The version number.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
+	}
+}
+
+func (r *AccountResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkRequiredCustomFields(ctx, r.client, req.Plan, &resp.Diagnostics)
+
+	// The ID of an account is only known once it exists, so an account being
+	// its own parent can't be caught by validating the configuration
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state AccountResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.ParentAccountId.IsUnknown() && !plan.ParentAccountId.IsNull() && plan.ParentAccountId.ValueString() == state.Id.ValueString() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("parent_account_id"),
+			"Account cannot be its own parent",
+			fmt.Sprintf("The account %s cannot be set as its own parent account.", state.Id.ValueString()),
+		)
+	}
+}
+
+func (r *AccountResource) read(ctx context.Context, data *AccountResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           restData,
+	}
+	m.to("id", &data.Id)
+	m.to("version", &data.Version)
+	m.to("name", &data.Name)
+	m.to("code", &data.Code)
+	m.to("emailAddress", &data.EmailAddress)
+	m.to("currency", &data.Currency)
+	m.to("daysBeforeBillDue", &data.DaysBeforeBillDue)
+	m.to("purchaseOrderNumber", &data.PurchaseOrderNumber)
+	m.to("parentAccountId", &data.ParentAccountId)
+	m.to("childBillingMode", &data.ChildBillingMode)
+	if data.ChildBillingMode.IsUnknown() {
+		data.ChildBillingMode = types.StringNull()
+	}
+	m.to("autoGenerateStatementMode", &data.AutoGenerateStatementMode)
+	m.listTo("creditApplicationOrder", &data.CreditApplicationOrder, types.StringType, func(v any) (attr.Value, diag.Diagnostics) {
+		sv, ok := v.(string)
+		if !ok {
+			return nil, diag.Diagnostics{diag.NewErrorDiagnostic("cannot map list element, expected string", "")}
+		}
+
+		return types.StringValue(sv), nil
+	})
+	m.customFieldsTo(&data.CustomFields)
+
+	if !data.Id.IsUnknown() && !data.Id.IsNull() {
+		data.Plans = r.readRelated(ctx, "/accountplans", data.Id.ValueString(), accountPlanEntryType, accountPlanEntryFields, nil, diagnostics)
+
+		// Only commitments which have not yet ended are active
+		today := time.Now().UTC().Format(time.DateOnly)
+		active := func(restData map[string]any) bool {
+			endDate, _ := restData["endDate"].(string)
+			return endDate == "" || endDate >= today
+		}
+		data.Commitments = r.readRelated(ctx, "/commitments", data.Id.ValueString(), accountCommitmentEntryType, accountCommitmentEntryFields, active, diagnostics)
+		data.ChildAccountIds = r.readChildAccountIds(ctx, data.Id.ValueString(), diagnostics)
+	}
+
+	addressTypes := make(map[string]attr.Type)
+	for k, v := range accountAddressAttributes {
+		addressTypes[k] = v.GetType()
+	}
+	m.objectTo("address", &data.Address, addressTypes, accountAddressFields)
+}
+
+// readRelated lists the entities at path belonging to the account, optionally
+// filtered by include, as a list of objects of the given type.
+func (r *AccountResource) readRelated(ctx context.Context, path, accountId string, entryType schema.NestedAttributeObject, fields map[string]string, include func(map[string]any) bool, diagnostics *diag.Diagnostics) types.List {
+	ts := make(map[string]attr.Type)
+	for k, v := range entryType.Attributes {
+		ts[k] = v.GetType()
+	}
+
+	query := url.Values{}
+	query.Set("accountId", accountId)
+
+	var elements []attr.Value
+	err := r.client.list(ctx, path, query, func(restData map[string]any) bool {
+		if id, _ := restData["accountId"].(string); id != accountId || (include != nil && !include(restData)) {
+			return true
+		}
+
+		attrs := make(map[string]attr.Value)
+		for k, field := range fields {
+			switch v := restData[field].(type) {
+			case string:
+				attrs[k] = types.StringValue(v)
+			case float64:
+				attrs[k] = types.Float64Value(v)
+			default:
+				if ts[k].Equal(types.Float64Type) {
+					attrs[k] = types.Float64Null()
+				} else {
+					attrs[k] = types.StringNull()
+				}
+			}
+		}
+		ov, diag := types.ObjectValue(ts, attrs)
+		diagnostics.Append(diag...)
+		elements = append(elements, ov)
+		return true
+	})
+	if err != nil {
+		diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list %s for account %s, got error: %s", strings.TrimPrefix(path, "/"), accountId, err))
+	}
+
+	lv, diag := types.ListValue(types.ObjectType{AttrTypes: ts}, elements)
+	diagnostics.Append(diag...)
+	return lv
+}
+
+// readChildAccountIds lists the IDs of the accounts whose parent is the
+// account, in ascending order.
+func (r *AccountResource) readChildAccountIds(ctx context.Context, accountId string, diagnostics *diag.Diagnostics) types.List {
+	var ids []string
+	err := r.client.list(ctx, "/accounts/"+url.PathEscape(accountId)+"/children", nil, func(restData map[string]any) bool {
+		if id, ok := restData["id"].(string); ok {
+			ids = append(ids, id)
+		}
+		return true
+	})
+	if err != nil {
+		diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list child accounts for account %s, got error: %s", accountId, err))
+	}
+	slices.Sort(ids)
+
+	elements := make([]attr.Value, 0, len(ids))
+	for _, id := range ids {
+		elements = append(elements, types.StringValue(id))
+	}
+	lv, diag := types.ListValue(types.StringType, elements)
+	diagnostics.Append(diag...)
+	return lv
+}
+
+func (r *AccountResource) write(ctx context.Context, data *AccountResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           restData,
+	}
+	m.from(data.Id, "id")
+	m.from(data.Version, "version")
+	m.from(data.Name, "name")
+	m.from(data.Code, "code")
+	m.from(data.EmailAddress, "emailAddress")
+	m.from(data.Currency, "currency")
+	m.from(data.DaysBeforeBillDue, "daysBeforeBillDue")
+	m.from(data.PurchaseOrderNumber, "purchaseOrderNumber")
+	m.from(data.ParentAccountId, "parentAccountId")
+	m.from(data.ChildBillingMode, "childBillingMode")
+	m.from(data.AutoGenerateStatementMode, "autoGenerateStatementMode")
+	m.listFrom(data.CreditApplicationOrder, "creditApplicationOrder", func(v attr.Value) (any, diag.Diagnostics) {
+		if sv, ok := v.(types.String); ok {
+			return sv.ValueString(), nil
+		}
+
+		return nil, diag.Diagnostics{diag.NewErrorDiagnostic("cannot map list element, expected string", "")}
+	})
+	m.customFieldsFrom(data.CustomFields)
+
+	m.objectFrom(data.Address, "address", accountAddressFields)
+}
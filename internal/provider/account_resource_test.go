@@ -0,0 +1,48 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAccountResourceImportByCodeOrEmail(t *testing.T) {
+	api := newFakeAPI(t)
+	// None of the accounts has children
+	api.handle(func(w http.ResponseWriter, req *fakeRequest) bool {
+		if req.Method != http.MethodGet || !strings.HasSuffix(req.Path, "/children") {
+			return false
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"data": []any{}})
+		return true
+	})
+	p := newTestProvider(t, api, nil)
+
+	api.put("accounts", map[string]any{"name": "Other", "code": "other", "emailAddress": "other@example.com"})
+	id := api.put("accounts", map[string]any{"name": "Acme", "code": "acme", "emailAddress": "billing@acme.example"})
+
+	// The code is looked up with the API's filter
+	state := p.importState("m3ter_account", "acme")
+	if v := attrValue(t, state, "id"); v != id {
+		t.Errorf("id = %v, want %s", v, id)
+	}
+	lists := api.requestsTo("GET", "/accounts")
+	if len(lists) == 0 || lists[0].Query.Get("codes") != "acme" {
+		t.Errorf("the account was not listed filtered by code")
+	}
+
+	// An email address matches no code, so every account is listed
+	api.clearRequests()
+	state = p.importState("m3ter_account", "billing@acme.example")
+	if v := attrValue(t, state, "id"); v != id {
+		t.Errorf("id = %v, want %s", v, id)
+	}
+
+	_, diags := p.tryImportState("m3ter_account", "missing")
+	if summary := diagnosticsSummary(diags); !hasErrors(diags) || !strings.Contains(summary, "Account not found") {
+		t.Errorf("got diagnostics %q, want the account not found", summary)
+	}
+}
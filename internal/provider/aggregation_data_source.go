@@ -5,8 +5,6 @@ package provider
 
 import (
 	"context"
-	"fmt"
-	"net/url"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -19,12 +17,28 @@ import (
 var _ datasource.DataSource = &AggregationDataSource{}
 
 func NewAggregationDataSource() datasource.DataSource {
-	return &AggregationDataSource{}
+	r := &AggregationDataSource{}
+	r.genericDataSource = genericDataSource[AggregationDataSourceModel, *AggregationDataSourceModel]{
+		typeName: "aggregation",
+		path:     "/aggregations",
+		name:     "aggregation",
+		filters: func(data *AggregationDataSourceModel) map[string]types.String {
+			return map[string]types.String{
+				"name": data.Name,
+				"code": data.Code,
+			}
+		},
+		extraQuery: func(data *AggregationDataSourceModel) types.Map {
+			return data.ExtraQuery
+		},
+		read: r.read,
+	}
+	return r
 }
 
 // AggregationDataSource defines the data source implementation.
 type AggregationDataSource struct {
-	client *m3terClient
+	genericDataSource[AggregationDataSourceModel, *AggregationDataSourceModel]
 }
 
 type AggregationDataSourceModel struct {
@@ -34,16 +48,13 @@ type AggregationDataSourceModel struct {
 	Segments     types.List    `tfsdk:"segments"`
 	Id           types.String  `tfsdk:"id"`
 	Version      types.Int64   `tfsdk:"version"`
+	ExtraQuery   types.Map     `tfsdk:"extra_query"`
 }
 
 func (r *AggregationDataSourceModel) GetId() types.String {
 	return r.Id
 }
 
-func (r *AggregationDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
-	resp.TypeName = req.ProviderTypeName + "_aggregation"
-}
-
 func (r *AggregationDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Aggregation data source",
@@ -60,7 +71,7 @@ func (r *AggregationDataSource) Schema(ctx context.Context, req datasource.Schem
 				Computed:            true,
 			},
 			"custom_fields": schema.DynamicAttribute{
-				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be either a string or a number.",
+				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be a string, a number or a boolean.",
 				Computed:            true,
 			},
 			"segments": schema.ListAttribute{
@@ -79,119 +90,11 @@ func (r *AggregationDataSource) Schema(ctx context.Context, req datasource.Schem
 				Computed:            true,
 				MarkdownDescription: "The version number.",
 			},
+			"extra_query": extraQueryAttribute,
 		},
 	}
 }
 
-func (r *AggregationDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
-	// Prevent panic if the provider has not been configured.
-	if req.ProviderData == nil {
-		return
-	}
-
-	client, ok := req.ProviderData.(*m3terClient)
-
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *m3terClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
-		)
-
-		return
-	}
-
-	r.client = client
-}
-
-func (r *AggregationDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
-	var data AggregationDataSourceModel
-
-	// Read Terraform prior state data into the model
-	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
-
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	if !data.Id.IsUnknown() && !data.Id.IsNull() {
-		var restData map[string]any
-		err := r.client.execute(ctx, "GET", "/aggregations/"+url.PathEscape(data.Id.ValueString()), nil, nil, &restData)
-		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read aggregation, got error: %s", err))
-			return
-		}
-
-		r.read(ctx, &data, restData, &resp.Diagnostics)
-
-		// Save updated data into Terraform state
-		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
-		return
-	}
-
-	var matches []map[string]any
-	queryParams := make(url.Values)
-	queryParams.Set("pageSize", "200")
-	for {
-		var response struct {
-			Data      []map[string]any `json:"data"`
-			NextToken string           `json:"nextToken"`
-		}
-		err := r.client.execute(ctx, "GET", "/aggregations", queryParams, nil, &response)
-		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list products, got error: %s", err))
-			return
-		}
-
-		for _, restData := range response.Data {
-			if !data.Name.IsUnknown() && !data.Name.IsNull() {
-				name := data.Name.ValueString()
-				productName, ok := restData["name"].(string)
-				if !ok {
-					continue
-				}
-				if productName != name {
-					continue
-				}
-			}
-
-			if !data.Code.IsUnknown() && !data.Code.IsNull() {
-				code := data.Code.ValueString()
-				productCode, ok := restData["code"].(string)
-				if !ok {
-					continue
-				}
-
-				if productCode != code {
-					continue
-				}
-			}
-
-			matches = append(matches, restData)
-		}
-
-		if response.NextToken == "" {
-			break
-		}
-
-		queryParams.Set("nextToken", response.NextToken)
-	}
-
-	if len(matches) == 0 {
-		resp.Diagnostics.AddError("No matching aggregation found", "No aggregation found matching the specified criteria")
-		return
-	}
-
-	if len(matches) > 1 {
-		resp.Diagnostics.AddError("Multiple matching aggregation found", "Multiple aggregation found matching the specified criteria")
-		return
-	}
-
-	r.read(ctx, &data, matches[0], &resp.Diagnostics)
-
-	// Save updated data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
-}
-
 func (r *AggregationDataSource) read(ctx context.Context, data *AggregationDataSourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
 	m := &mapper{
 		ctx:         ctx,
@@ -6,16 +6,19 @@ package provider
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"net/url"
 	"regexp"
+	"strings"
 
-	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -25,6 +28,7 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &AggregationResource{}
 var _ resource.ResourceWithImportState = &AggregationResource{}
+var _ resource.ResourceWithModifyPlan = &AggregationResource{}
 
 func NewAggregationResource() resource.Resource {
 	return &AggregationResource{}
@@ -37,20 +41,24 @@ type AggregationResource struct {
 
 // AggregationResourceModel describes the resource data model.
 type AggregationResourceModel struct {
-	Name            types.String  `tfsdk:"name"`
-	CustomFields    types.Dynamic `tfsdk:"custom_fields"`
-	Rounding        types.String  `tfsdk:"rounding"`
-	QuantityPerUnit types.Float64 `tfsdk:"quantity_per_unit"`
-	Unit            types.String  `tfsdk:"unit"`
-	Code            types.String  `tfsdk:"code"`
-	MeterId         types.String  `tfsdk:"meter_id"`
-	TargetField     types.String  `tfsdk:"target_field"`
-	Aggregation     types.String  `tfsdk:"aggregation"`
-	SegmentedFields types.List    `tfsdk:"segmented_fields"`
-	Segments        types.List    `tfsdk:"segments"`
-	DefaultValue    types.Float64 `tfsdk:"default_value"`
-	Id              types.String  `tfsdk:"id"`
-	Version         types.Int64   `tfsdk:"version"`
+	Name                     types.String   `tfsdk:"name"`
+	CustomFields             types.Dynamic  `tfsdk:"custom_fields"`
+	Rounding                 types.String   `tfsdk:"rounding"`
+	QuantityPerUnit          types.Number   `tfsdk:"quantity_per_unit"`
+	Unit                     types.String   `tfsdk:"unit"`
+	Code                     types.String   `tfsdk:"code"`
+	MeterId                  types.String   `tfsdk:"meter_id"`
+	TargetField              types.String   `tfsdk:"target_field"`
+	Aggregation              types.String   `tfsdk:"aggregation"`
+	SegmentedFields          types.List     `tfsdk:"segmented_fields"`
+	Segments                 types.List     `tfsdk:"segments"`
+	DefaultValue             types.Float64  `tfsdk:"default_value"`
+	AccountingProductId      types.String   `tfsdk:"accounting_product_id"`
+	EvaluateNullAggregations types.Bool     `tfsdk:"evaluate_null_aggregations"`
+	ForceDestroy             types.Bool     `tfsdk:"force_destroy"`
+	Id                       types.String   `tfsdk:"id"`
+	Version                  types.Int64    `tfsdk:"version"`
+	Timeouts                 timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *AggregationResourceModel) GetId() types.String {
@@ -71,7 +79,7 @@ func (r *AggregationResource) Schema(ctx context.Context, req resource.SchemaReq
 				Required:            true,
 			},
 			"custom_fields": schema.DynamicAttribute{
-				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be either a string or a number.",
+				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be a string, a number or a boolean.",
 				Required:            true,
 			},
 			"rounding": schema.StringAttribute{
@@ -81,11 +89,12 @@ func (r *AggregationResource) Schema(ctx context.Context, req resource.SchemaReq
 					stringvalidator.OneOf("UP", "DOWN", "NEAREST", "NONE"),
 				},
 			},
-			"quantity_per_unit": schema.Float64Attribute{
-				MarkdownDescription: "Defines how much of a quantity equates to 1 unit. Used when setting the price per unit for billing purposes - if charging for kilobytes per second (KiBy/s) at rate of $0.25 per 500 KiBy/s, then set quantityPerUnit to 500 and price Plan at $0.25 per unit.",
+			"quantity_per_unit": schema.NumberAttribute{
+				MarkdownDescription: "Defines how much of a quantity equates to 1 unit. Used when setting the price per unit for billing purposes - if charging for kilobytes per second (KiBy/s) at rate of $0.25 per 500 KiBy/s, then set quantityPerUnit to 500 and price Plan at $0.25 per unit. Integer quantities must be exactly representable as a 64-bit floating point number, which every integer up to 2^53 is.",
 				Required:            true,
-				Validators: []validator.Float64{
-					float64validator.AtLeast(0),
+				Validators: []validator.Number{
+					numberAtLeast(0),
+					exactNumber(),
 				},
 			},
 			"unit": schema.StringAttribute{
@@ -134,6 +143,24 @@ func (r *AggregationResource) Schema(ctx context.Context, req resource.SchemaReq
 				MarkdownDescription: "Aggregation value used when no usage data is available to be aggregated.",
 				Optional:            true,
 			},
+			"accounting_product_id": schema.StringAttribute{
+				MarkdownDescription: "Optional Product ID this Aggregation should be attributed to for accounting purposes.",
+				Optional:            true,
+			},
+			"evaluate_null_aggregations": schema.BoolAttribute{
+				MarkdownDescription: "Whether the Aggregation is evaluated, using the default value, for periods in which no usage data was received.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"force_destroy": schema.BoolAttribute{
+				MarkdownDescription: "When true, any Pricings using the Aggregation are deleted before the Aggregation is destroyed. Otherwise destroying an Aggregation that is in use fails, naming the Pricings that use it.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
 			"id": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "The UUID of the entity.",
@@ -146,6 +173,9 @@ func (r *AggregationResource) Schema(ctx context.Context, req resource.SchemaReq
 				MarkdownDescription: "The version number.",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -182,39 +212,72 @@ func (r *AggregationResource) Update(ctx context.Context, req resource.UpdateReq
 }
 
 func (r *AggregationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	genericDelete[AggregationResourceModel](ctx, req, resp, r.client, "/aggregations", "aggregation")
-}
+	ctx, cancel := withOperationTimeout(ctx, req.State, "delete", &resp.Diagnostics)
+	defer cancel()
+	ctx, logCalls := countAPICalls(ctx, "delete", "aggregation")
+	defer logCalls()
+
+	var data AggregationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-func (r *AggregationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	var restData map[string]any
-	err := r.client.execute(ctx, "GET", "/aggregations/"+url.PathEscape(req.ID), nil, nil, &restData)
+	if data.ForceDestroy.ValueBool() {
+		deleteAggregationPricings(ctx, r.client, data.Id.ValueString(), &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	err := r.client.execute(ctx, "DELETE", "/aggregations/"+url.PathEscape(data.Id.ValueString()), nil, nil, nil)
+	// The aggregation was already deleted outside Terraform
 	if sc, ok := err.(*statusCodeError); ok && sc.StatusCode == 404 {
-		urlValues := url.Values{}
-		urlValues.Set("pageSize", "1")
-		urlValues.Set("codes", req.ID)
-
-		var aggregationListResponse struct {
-			Data []struct {
-				Id      string `json:"id"`
-				Code    string `json:"code"`
-				Version int64  `json:"version"`
-			} `json:"data"`
-			NextToken string `json:"next_token"`
+		return
+	}
+	if isConflict(err) {
+		// The pricings are only looked up to explain why the aggregation
+		// can't be deleted
+		pricingIds, listErr := findDependents(ctx, r.client, "/pricings", "aggregationId", data.Id.ValueString())
+		if listErr != nil || len(pricingIds) == 0 {
+			resp.Diagnostics.AddError("Aggregation in use", fmt.Sprintf("Aggregation %s is in use. Delete its dependents first or set force_destroy to delete them along with the aggregation. Got error: %s", data.Id.ValueString(), err))
+			return
+		}
+		resp.Diagnostics.AddError("Aggregation in use", fmt.Sprintf("Aggregation %s is used by pricings %s. Delete them first or set force_destroy to delete them along with the aggregation. Got error: %s", data.Id.ValueString(), strings.Join(pricingIds, ", "), err))
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete aggregation, got error: %s", err))
+	}
+}
+
+// deleteAggregationPricings deletes the pricings using an aggregation, so
+// that it can be deleted.
+func deleteAggregationPricings(ctx context.Context, client *m3terClient, id string, diagnostics *diag.Diagnostics) {
+	pricingIds, err := findDependents(ctx, client, "/pricings", "aggregationId", id)
+	if err != nil {
+		diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list pricings for aggregation %s, got error: %s", id, err))
+		return
+	}
+
+	for _, pricingId := range pricingIds {
+		err := client.execute(ctx, "DELETE", "/pricings/"+url.PathEscape(pricingId), nil, nil, nil)
+		if sc, ok := err.(*statusCodeError); ok && sc.StatusCode == 404 {
+			continue
 		}
-		err := r.client.execute(ctx, "GET", "/aggregations", nil, nil, &aggregationListResponse)
 		if err != nil {
-			resp.Diagnostics.AddError("Failed to list aggregations", err.Error())
+			diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete pricing %s, got error: %s", pricingId, err))
 			return
 		}
-		for _, aggregation := range aggregationListResponse.Data {
-			if aggregation.Code == req.ID {
-				resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), aggregation.Id)...)
-				return
-			}
-		}
-		resp.Diagnostics.AddError("Aggregation not found", "The aggregation with code "+req.ID+" does not exist.")
 	}
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *AggregationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	genericImportState(ctx, req, resp, r.client, "/aggregations", "aggregation", []string{"code"})
+}
+
+func (r *AggregationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkRequiredCustomFields(ctx, r.client, req.Plan, &resp.Diagnostics)
 }
 
 func (r *AggregationResource) read(ctx context.Context, data *AggregationResourceModel, restModel map[string]any, diagnostics *diag.Diagnostics) {
@@ -229,7 +292,17 @@ func (r *AggregationResource) read(ctx context.Context, data *AggregationResourc
 	m.to("name", &data.Name)
 	m.customFieldsTo(&data.CustomFields)
 	m.to("rounding", &data.Rounding)
-	m.to("quantityPerUnit", &data.QuantityPerUnit)
+	// The API returns the quantity as a double, so a prior value it was
+	// rounded from is kept rather than replaced by the approximation
+	markMapped(ctx, "quantityPerUnit")
+	if quantity, ok := restModel["quantityPerUnit"].(float64); ok {
+		prior := data.QuantityPerUnit
+		if prior.IsNull() || prior.IsUnknown() {
+			data.QuantityPerUnit = types.NumberValue(big.NewFloat(quantity))
+		} else if rounded, _ := prior.ValueBigFloat().Float64(); rounded != quantity {
+			data.QuantityPerUnit = types.NumberValue(big.NewFloat(quantity))
+		}
+	}
 	m.to("unit", &data.Unit)
 	m.to("code", &data.Code)
 	m.to("meterId", &data.MeterId)
@@ -265,6 +338,16 @@ func (r *AggregationResource) read(ctx context.Context, data *AggregationResourc
 	})
 
 	m.to("defaultValue", &data.DefaultValue)
+	m.to("accountingProductId", &data.AccountingProductId)
+	m.to("evaluateNullAggregations", &data.EvaluateNullAggregations)
+	if data.EvaluateNullAggregations.IsUnknown() {
+		data.EvaluateNullAggregations = types.BoolValue(false)
+	}
+	// The API doesn't store force_destroy, so imported aggregations start
+	// from its default
+	if data.ForceDestroy.IsNull() {
+		data.ForceDestroy = types.BoolValue(false)
+	}
 }
 
 func (r *AggregationResource) write(ctx context.Context, data *AggregationResourceModel, restModel map[string]any, diagnostics *diag.Diagnostics) {
@@ -313,4 +396,9 @@ func (r *AggregationResource) write(ctx context.Context, data *AggregationResour
 	if data.DefaultValue.IsNull() && !data.DefaultValue.IsUnknown() {
 		m.v["defaultValue"] = nil
 	}
+	m.from(data.AccountingProductId, "accountingProductId")
+	if data.AccountingProductId.IsNull() {
+		delete(m.v, "accountingProductId")
+	}
+	m.from(data.EvaluateNullAggregations, "evaluateNullAggregations")
 }
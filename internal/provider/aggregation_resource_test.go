@@ -0,0 +1,142 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAggregationResourceRoundTrip(t *testing.T) {
+	api := newFakeAPI(t)
+	// Strict mode fails on any field of the payload the provider drops
+	p := newTestProvider(t, api, map[string]any{"strict_mode": true})
+
+	meterId := api.put("meters", map[string]any{"code": "api_calls"})
+	id := api.put("aggregations", map[string]any{
+		"version":                  float64(4),
+		"name":                     "API calls per region",
+		"code":                     "api_calls_per_region",
+		"meterId":                  meterId,
+		"targetField":              "calls",
+		"aggregation":              "SUM",
+		"rounding":                 "UP",
+		"quantityPerUnit":          float64(1000),
+		"unit":                     "{call}",
+		"segmentedFields":          []any{"region"},
+		"segments":                 []any{map[string]any{"region": "eu"}, map[string]any{"region": "us"}},
+		"defaultValue":             float64(0),
+		"accountingProductId":      "00000000-0000-4000-8000-000000000002",
+		"evaluateNullAggregations": true,
+		"customFields":             map[string]any{"team": "billing", "priority": float64(2)},
+		"createdBy":                "someone@example.com",
+		"lastModifiedBy":           "someone@example.com",
+		"dtCreated":                "2024-01-01T00:00:00Z",
+		"dtLastModified":           "2024-02-01T00:00:00Z",
+	})
+
+	state := p.importState("m3ter_aggregation", id)
+
+	config := map[string]any{
+		"name":                       "API calls per region",
+		"code":                       "api_calls_per_region",
+		"meter_id":                   meterId,
+		"target_field":               "calls",
+		"aggregation":                "SUM",
+		"rounding":                   "UP",
+		"quantity_per_unit":          1000,
+		"unit":                       "{call}",
+		"segmented_fields":           []any{"region"},
+		"segments":                   []any{map[string]any{"region": "eu"}, map[string]any{"region": "us"}},
+		"default_value":              0,
+		"accounting_product_id":      "00000000-0000-4000-8000-000000000002",
+		"evaluate_null_aggregations": true,
+		"custom_fields":              map[string]any{"team": "billing", "priority": 2},
+	}
+	p.assertNoChanges("m3ter_aggregation", state, config)
+
+	// Updating the aggregation sends back every field
+	config["name"] = "API calls by region"
+	p.update("m3ter_aggregation", state, config)
+	sent := api.requestsTo("PUT", "/aggregations/"+id)[0].Body
+	for _, key := range []string{"segmentedFields", "segments", "defaultValue", "accountingProductId", "evaluateNullAggregations", "customFields", "quantityPerUnit"} {
+		if _, ok := sent[key]; !ok {
+			t.Errorf("%s was not sent", key)
+		}
+	}
+}
+
+func TestAggregationResourceQuantityPerUnit(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	config := map[string]any{
+		"name":          "Storage",
+		"code":          "storage",
+		"meter_id":      api.put("meters", map[string]any{"code": "storage"}),
+		"target_field":  "bytes",
+		"aggregation":   "SUM",
+		"rounding":      "NONE",
+		"unit":          "By",
+		"default_value": 0,
+		"custom_fields": map[string]any{},
+	}
+
+	// The largest integers a double holds exactly are sent unchanged
+	config["quantity_per_unit"] = "9007199254740992"
+	state := p.create("m3ter_aggregation", config)
+	id := attrValue(t, state, "id").(string)
+	if v := api.get("aggregations", id)["quantityPerUnit"]; v != float64(1<<53) {
+		t.Errorf("sent quantityPerUnit %v, want 2^53", v)
+	}
+	state = p.read("m3ter_aggregation", state)
+	p.assertNoChanges("m3ter_aggregation", state, config)
+
+	// A fraction is kept as configured rather than as the double read back
+	config["quantity_per_unit"] = "0.1"
+	state = p.update("m3ter_aggregation", state, config)
+	state = p.read("m3ter_aggregation", state)
+	p.assertNoChanges("m3ter_aggregation", state, config)
+
+	// Larger integers would be rounded, so they are rejected
+	config["quantity_per_unit"] = "9007199254740993"
+	_, diags := p.plan("m3ter_aggregation", state, config)
+	if summary := diagnosticsSummary(diags); !strings.Contains(summary, "Value out of exact range") {
+		t.Errorf("got diagnostics %q, want 2^53+1 rejected", summary)
+	}
+	config["quantity_per_unit"] = -1
+	_, diags = p.plan("m3ter_aggregation", state, config)
+	if !hasErrors(diags) {
+		t.Errorf("a negative quantity_per_unit was accepted")
+	}
+}
+
+func TestAggregationResourceNullDefaultValue(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	// An unset default value is sent as null to clear it, and returned as
+	// null by the API
+	config := map[string]any{
+		"name":              "Storage",
+		"code":              "storage",
+		"meter_id":          api.put("meters", map[string]any{"code": "storage"}),
+		"target_field":      "bytes",
+		"aggregation":       "SUM",
+		"rounding":          "NONE",
+		"quantity_per_unit": 1,
+		"unit":              "By",
+		"custom_fields":     map[string]any{},
+	}
+	state := p.create("m3ter_aggregation", config)
+	id := attrValue(t, state, "id").(string)
+	if v, ok := api.get("aggregations", id)["defaultValue"]; !ok || v != nil {
+		t.Fatalf("stored defaultValue = %v, want null", v)
+	}
+	state = p.read("m3ter_aggregation", state)
+	if v := attrValue(t, state, "default_value"); v != nil {
+		t.Errorf("default_value = %v, want null", v)
+	}
+	p.assertNoChanges("m3ter_aggregation", state, config)
+}
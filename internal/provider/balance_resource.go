@@ -0,0 +1,228 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &BalanceResource{}
+var _ resource.ResourceWithImportState = &BalanceResource{}
+var _ resource.ResourceWithModifyPlan = &BalanceResource{}
+
+func NewBalanceResource() resource.Resource {
+	r := &BalanceResource{}
+	r.genericResource = genericResource[BalanceResourceModel, *BalanceResourceModel]{
+		typeName: "balance",
+		path:     "/balances",
+		name:     "balance",
+		read:     r.read,
+		write:    r.write,
+	}
+	return r
+}
+
+// BalanceResource defines the resource implementation.
+type BalanceResource struct {
+	genericResource[BalanceResourceModel, *BalanceResourceModel]
+}
+
+// BalanceResourceModel describes the resource data model.
+type BalanceResourceModel struct {
+	AccountId                       types.String   `tfsdk:"account_id"`
+	Currency                        types.String   `tfsdk:"currency"`
+	StartDate                       types.String   `tfsdk:"start_date"`
+	EndDate                         types.String   `tfsdk:"end_date"`
+	Amount                          types.Float64  `tfsdk:"amount"`
+	Description                     types.String   `tfsdk:"description"`
+	Name                            types.String   `tfsdk:"name"`
+	RolloverAmount                  types.Float64  `tfsdk:"rollover_amount"`
+	RolloverEndDate                 types.String   `tfsdk:"rollover_end_date"`
+	ConsumptionsAccountingProductId types.String   `tfsdk:"consumptions_accounting_product_id"`
+	FeesAccountingProductId         types.String   `tfsdk:"fees_accounting_product_id"`
+	LineItemTypes                   types.List     `tfsdk:"line_item_types"`
+	OverageSurchargePercent         types.Float64  `tfsdk:"overage_surcharge_percent"`
+	CustomFields                    types.Dynamic  `tfsdk:"custom_fields"`
+	Id                              types.String   `tfsdk:"id"`
+	Version                         types.Int64    `tfsdk:"version"`
+	Timeouts                        timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *BalanceResourceModel) GetId() types.String {
+	return r.Id
+}
+
+func (r *BalanceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Balance resource",
+
+		Attributes: map[string]schema.Attribute{
+			"account_id": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the Account the Balance belongs to.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"currency": schema.StringAttribute{
+				MarkdownDescription: "The currency of the Balance, such as USD or GBP.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(3, 3),
+				},
+			},
+			"start_date": schema.StringAttribute{
+				MarkdownDescription: "The date (in ISO-8601 format) from which the Balance can be drawn down.",
+				Required:            true,
+				Validators: []validator.String{
+					iso8601(),
+				},
+			},
+			"end_date": schema.StringAttribute{
+				MarkdownDescription: "The date (in ISO-8601 format) after which the Balance can no longer be drawn down.",
+				Required:            true,
+				Validators: []validator.String{
+					iso8601(),
+				},
+			},
+			"amount": schema.Float64Attribute{
+				MarkdownDescription: "The amount of the Balance.",
+				Optional:            true,
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "A description of the Balance.",
+				Optional:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the Balance.",
+				Optional:            true,
+			},
+			"rollover_amount": schema.Float64Attribute{
+				MarkdownDescription: "The maximum amount of the Balance that can be rolled over once it ends.",
+				Optional:            true,
+			},
+			"rollover_end_date": schema.StringAttribute{
+				MarkdownDescription: "The date (in ISO-8601 format) until which any rolled over amount can be drawn down.",
+				Optional:            true,
+			},
+			"consumptions_accounting_product_id": schema.StringAttribute{
+				MarkdownDescription: "Optional Product ID the Balance consumptions should be attributed to for accounting purposes.",
+				Optional:            true,
+			},
+			"fees_accounting_product_id": schema.StringAttribute{
+				MarkdownDescription: "Optional Product ID the Balance fees should be attributed to for accounting purposes.",
+				Optional:            true,
+			},
+			"line_item_types": schema.ListAttribute{
+				MarkdownDescription: "The types of Bill line items the Balance can be drawn down against.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"overage_surcharge_percent": schema.Float64Attribute{
+				MarkdownDescription: "The percentage surcharge applied to usage charges exceeding the Balance amount.",
+				Optional:            true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+			"custom_fields": schema.DynamicAttribute{
+				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be a string, a number or a boolean.",
+				Required:            true,
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The UUID of the entity.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"version": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The version number.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
+	}
+}
+
+func (r *BalanceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkRequiredCustomFields(ctx, r.client, req.Plan, &resp.Diagnostics)
+}
+
+func (r *BalanceResource) read(ctx context.Context, data *BalanceResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           restData,
+	}
+	m.to("id", &data.Id)
+	m.to("version", &data.Version)
+	m.to("accountId", &data.AccountId)
+	m.to("currency", &data.Currency)
+	m.to("startDate", &data.StartDate)
+	m.to("endDate", &data.EndDate)
+	m.to("amount", &data.Amount)
+	m.to("description", &data.Description)
+	m.to("name", &data.Name)
+	m.to("rolloverAmount", &data.RolloverAmount)
+	m.to("rolloverEndDate", &data.RolloverEndDate)
+	m.to("consumptionsAccountingProductId", &data.ConsumptionsAccountingProductId)
+	m.to("feesAccountingProductId", &data.FeesAccountingProductId)
+	m.to("overageSurchargePercent", &data.OverageSurchargePercent)
+	m.customFieldsTo(&data.CustomFields)
+
+	m.listTo("lineItemTypes", &data.LineItemTypes, types.StringType, func(v any) (attr.Value, diag.Diagnostics) {
+		if s, ok := v.(string); ok {
+			return types.StringValue(s), nil
+		}
+
+		return nil, diag.Diagnostics{diag.NewErrorDiagnostic("cannot map list element, expected string", "")}
+	})
+}
+
+func (r *BalanceResource) write(ctx context.Context, data *BalanceResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           restData,
+	}
+	m.from(data.Id, "id")
+	m.from(data.Version, "version")
+	m.from(data.AccountId, "accountId")
+	m.from(data.Currency, "currency")
+	m.from(data.StartDate, "startDate")
+	m.from(data.EndDate, "endDate")
+	m.from(data.Amount, "amount")
+	m.from(data.Description, "description")
+	m.from(data.Name, "name")
+	m.from(data.RolloverAmount, "rolloverAmount")
+	m.from(data.RolloverEndDate, "rolloverEndDate")
+	m.from(data.ConsumptionsAccountingProductId, "consumptionsAccountingProductId")
+	m.from(data.FeesAccountingProductId, "feesAccountingProductId")
+	m.from(data.OverageSurchargePercent, "overageSurchargePercent")
+	m.customFieldsFrom(data.CustomFields)
+
+	m.listFrom(data.LineItemTypes, "lineItemTypes", func(v attr.Value) (any, diag.Diagnostics) {
+		if sv, ok := v.(types.String); ok {
+			return sv.ValueString(), nil
+		}
+
+		return nil, diag.Diagnostics{diag.NewErrorDiagnostic("cannot map list element, expected string", "")}
+	})
+}
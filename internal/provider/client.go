@@ -6,75 +6,629 @@ package provider
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
 	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 	"golang.org/x/time/rate"
 )
 
+// maxErrorBodySize bounds how much of an error response body is read into
+// the returned error.
+const maxErrorBodySize = 64 * 1024
+
+// defaultBaseURL is the base URL of the m3ter API in the US region.
+const defaultBaseURL = "https://api.m3ter.com"
+
+const (
+	// defaultMaxRetries is how many times a request failing with a 429 or 5xx
+	// status is retried by default
+	defaultMaxRetries = 3
+	// defaultRetryBaseDelay is the delay before the first retry by default,
+	// doubled for every further retry
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	// maxRetryDelay bounds the delay between retries
+	maxRetryDelay = 30 * time.Second
+	// defaultRequestTimeout bounds how long a single request may take by
+	// default
+	defaultRequestTimeout = time.Minute
+	// defaultRateLimit is how many read, and separately write, requests are
+	// sent per second at most by default
+	defaultRateLimit = 10
+)
+
 type m3terClient struct {
+	// baseURL is the base URL of the API, without a trailing slash
+	baseURL        string
 	organizationID string
-	client         *http.Client
-	limit          *rate.Limiter
+	credentials    *clientcredentials.Config
+	// readLimit and writeLimit throttle read (GET/HEAD) and write requests
+	// respectively, so heavy reads don't consume the write budget
+	readLimit  *rate.Limiter
+	writeLimit *rate.Limiter
+	// requiredCustomFields are the custom field keys every resource with
+	// custom fields must set
+	requiredCustomFields []string
+	// strictMode turns API response fields the provider doesn't map into
+	// errors rather than debug logs
+	strictMode bool
+	// maxRetries and retryBaseDelay control how requests failing with a 429
+	// or 5xx status are retried
+	maxRetries     int
+	retryBaseDelay time.Duration
+	// requestTimeout bounds each attempt at a request, so a hung endpoint
+	// can't block an apply
+	requestTimeout time.Duration
+	// userAgent identifies the provider and its version in requests
+	userAgent string
+	// listCache caches the entities listed by data sources, or is nil when
+	// caching is disabled
+	listCache *listCache
+	// transport carries both API requests and the OAuth token exchange, so
+	// proxy and TLS settings apply to both
+	transport *http.Transport
+
+	mu     sync.Mutex
+	client *http.Client
 }
 
-func (c *m3terClient) execute(ctx context.Context, method string, path string, query url.Values, requestBody any, responseBody any) error {
-	err := c.limit.Wait(ctx)
+func newM3terClient(baseURL, organizationID string, credentials *clientcredentials.Config, readLimit, writeLimit *rate.Limiter) *m3terClient {
+	// Honor HTTPS_PROXY, HTTP_PROXY and NO_PROXY unless a proxy is set with
+	// setProxy
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	c := &m3terClient{
+		baseURL:        baseURL,
+		organizationID: organizationID,
+		credentials:    credentials,
+		readLimit:      readLimit,
+		writeLimit:     writeLimit,
+		maxRetries:     defaultMaxRetries,
+		retryBaseDelay: defaultRetryBaseDelay,
+		requestTimeout: defaultRequestTimeout,
+		listCache:      newListCache(defaultListCacheSize),
+		transport:      transport,
+	}
+	c.client = c.newOAuthClient()
+	return c
+}
+
+// newOAuthClient returns an HTTP client authenticating with a new token
+// source, which fetches its tokens through the client transport.
+func (c *m3terClient) newOAuthClient() *http.Client {
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: c.transport})
+	return c.credentials.Client(ctx)
+}
+
+// setProxy sends all requests through the proxy at the given URL rather than
+// the one set in the environment.
+func (c *m3terClient) setProxy(proxyURL *url.URL) {
+	c.transport.Proxy = http.ProxyURL(proxyURL)
+}
+
+// setRootCAs verifies the certificates of the API, the token endpoint and
+// any https proxy against the given pool.
+func (c *m3terClient) setRootCAs(pool *x509.CertPool) {
+	if c.transport.TLSClientConfig == nil {
+		c.transport.TLSClientConfig = &tls.Config{}
+	}
+	c.transport.TLSClientConfig.RootCAs = pool
+}
+
+// loadCACertificates returns the system certificate pool with the PEM
+// certificates in s added, s being either inline PEM or the path of a PEM
+// file.
+func loadCACertificates(s string) (*x509.CertPool, error) {
+	pemData := []byte(s)
+	if !strings.Contains(s, "-----BEGIN") {
+		var err error
+		pemData, err = os.ReadFile(s)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pool, err := x509.SystemCertPool()
 	if err != nil {
-		return err
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pemData) {
+		return nil, fmt.Errorf("no PEM encoded certificate could be parsed")
 	}
-	fullURL := "https://api.m3ter.com/organizations/" + url.PathEscape(c.organizationID) + path
+	return pool, nil
+}
+
+func (c *m3terClient) httpClient() *http.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.client
+}
+
+// refreshToken replaces the HTTP client with one using a fresh token source,
+// forcing a new access token to be fetched on the next request.
+func (c *m3terClient) refreshToken() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.client = c.newOAuthClient()
+}
+
+func (c *m3terClient) execute(ctx context.Context, method string, path string, query url.Values, requestBody any, responseBody any) error {
+	return c.executeRaw(ctx, method, "/organizations/"+url.PathEscape(c.organizationID)+path, query, requestBody, responseBody)
+}
+
+// executeRaw sends a request to a path of the API which is not scoped to the
+// organization.
+func (c *m3terClient) executeRaw(ctx context.Context, method string, path string, query url.Values, requestBody any, responseBody any) error {
+	return c.executeURL(ctx, method, c.baseURL+path, query, requestBody, responseBody)
+}
+
+// executeURL sends a request to an absolute API URL, such as the location of
+// a created entity.
+func (c *m3terClient) executeURL(ctx context.Context, method string, fullURL string, query url.Values, requestBody any, responseBody any) error {
 	if query != nil {
 		fullURL += "?" + query.Encode()
 	}
 
-	var requestBodyReader io.Reader
+	var body []byte
 	if requestBody != nil {
-		body, err := json.Marshal(requestBody)
+		var err error
+		body, err = json.Marshal(requestBody)
 		if err != nil {
 			return err
 		}
-		requestBodyReader = bytes.NewReader(body)
 	}
-	req, err := http.NewRequestWithContext(ctx, method, fullURL, requestBodyReader)
-	if err != nil {
-		return err
+
+	// Writes may change the entities of cached lists, including lists being
+	// fetched while the write is in progress
+	if c.listCache != nil && method != http.MethodGet && method != http.MethodHead {
+		c.listCache.clear()
+		defer c.listCache.clear()
 	}
 
-	resp, err := c.client.Do(req)
+	resp, err := c.sendWithRetries(ctx, method, fullURL, body)
 	if err != nil {
 		return err
 	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		// The access token may have expired without the transport noticing, so
+		// retry once with a freshly fetched token
+		resp.Body.Close()
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		c.refreshToken()
+		resp, err = c.sendWithRetries(ctx, method, fullURL, body)
+		if err != nil {
+			return err
+		}
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, err := io.ReadAll(resp.Body)
+		requestID := responseRequestID(resp)
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
 		if err != nil {
-			return &statusCodeError{StatusCode: resp.StatusCode}
+			return &statusCodeError{StatusCode: resp.StatusCode, RequestID: requestID}
 		}
-		return &statusCodeError{StatusCode: resp.StatusCode, Body: string(body)}
+		return &statusCodeError{StatusCode: resp.StatusCode, Body: string(body), RequestID: requestID}
+	}
+
+	if responseBody == nil {
+		return nil
 	}
 
-	if responseBody != nil {
-		err = json.NewDecoder(resp.Body).Decode(responseBody)
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	tflog.Trace(ctx, "m3ter API response body", map[string]any{
+		"method": method,
+		"url":    fullURL,
+		"body":   redactedBody(respBody),
+	})
+
+	// An empty body leaves responseBody untouched, as with 204 No Content
+	empty := len(bytes.TrimSpace(respBody)) == 0
+	if !empty {
+		if err := json.Unmarshal(respBody, responseBody); err != nil {
+			return err
+		}
+	}
+
+	// Some endpoints respond to a create with the location of the entity
+	// rather than the entity itself, so fetch it from there
+	if location := resp.Header.Get("Location"); empty && location != "" && method != http.MethodGet {
+		base, err := url.Parse(fullURL)
 		if err != nil {
 			return err
 		}
+		ref, err := url.Parse(location)
+		if err != nil {
+			return err
+		}
+		return c.executeURL(ctx, http.MethodGet, base.ResolveReference(ref).String(), nil, nil, responseBody)
 	}
 	return nil
 }
 
+// isRetryable returns whether a request which got the given status may be
+// sent again. Rate limited requests were not processed, so are always
+// retried, while server errors are only retried for idempotent methods as
+// the request may have been processed.
+func isRetryable(method string, statusCode int) bool {
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if statusCode < 500 {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter returns how long the Retry-After header of the response asks
+// to wait, given either in seconds or as an HTTP date. A missing or invalid
+// header, or a date in the past, means no wait.
+func parseRetryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+
+	// Some servers send RFC1123 dates in a zone other than GMT
+	for _, parse := range []func(string) (time.Time, error){
+		http.ParseTime,
+		func(value string) (time.Time, error) { return time.Parse(time.RFC1123, value) },
+	} {
+		if date, err := parse(value); err == nil {
+			return max(time.Until(date), 0)
+		}
+	}
+	return 0
+}
+
+// retryDelay returns how long to wait before the given retry, counting from
+// zero, using the Retry-After header of the response when it asks to wait and
+// exponential backoff with jitter otherwise.
+func (c *m3terClient) retryDelay(resp *http.Response, retry int) time.Duration {
+	if delay := parseRetryAfter(resp); delay > 0 {
+		return delay
+	}
+
+	delay := c.retryBaseDelay << retry
+	if delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	// Full jitter spreads out the retries of concurrent requests
+	return time.Duration(rand.Int64N(int64(delay) + 1))
+}
+
+// sendWithRetries sends a request, retrying it while it fails with a
+// retryable status and the retries are not exhausted.
+func (c *m3terClient) sendWithRetries(ctx context.Context, method string, fullURL string, body []byte) (*http.Response, error) {
+	for retry := 0; ; retry++ {
+		resp, err := c.send(ctx, method, fullURL, body)
+		if err != nil || retry >= c.maxRetries || !isRetryable(method, resp.StatusCode) {
+			return resp, err
+		}
+
+		// Fail with the response rather than wait past the deadline
+		delay := c.retryDelay(resp, retry)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, nil
+		}
+
+		resp.Body.Close()
+		tflog.Debug(ctx, "Retrying m3ter API request", map[string]any{
+			"method":      method,
+			"url":         fullURL,
+			"status_code": resp.StatusCode,
+			"delay":       delay.String(),
+		})
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *m3terClient) send(ctx context.Context, method string, fullURL string, body []byte) (*http.Response, error) {
+	if calls, ok := ctx.Value(apiCallsKey{}).(*atomic.Int64); ok {
+		calls.Add(1)
+	}
+
+	limit := c.writeLimit
+	if method == http.MethodGet || method == http.MethodHead {
+		limit = c.readLimit
+	}
+	err := limit.Wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var requestBodyReader io.Reader
+	if body != nil {
+		requestBodyReader = bytes.NewReader(body)
+	}
+
+	// Each attempt gets its own timeout, which lasts until the response body
+	// is closed, not counting the wait for the rate limiter
+	requestCtx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	req, err := http.NewRequestWithContext(requestCtx, method, fullURL, requestBodyReader)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	if body != nil {
+		tflog.Trace(ctx, "m3ter API request body", map[string]any{
+			"method": method,
+			"url":    fullURL,
+			"body":   redactedBody(body),
+		})
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		cancel()
+		tflog.Debug(ctx, "m3ter API request failed", map[string]any{
+			"method":   method,
+			"url":      fullURL,
+			"duration": time.Since(start).String(),
+			"error":    err.Error(),
+		})
+		return nil, err
+	}
+	tflog.Debug(ctx, "m3ter API request", map[string]any{
+		"method":      method,
+		"url":         fullURL,
+		"status_code": resp.StatusCode,
+		"request_id":  responseRequestID(resp),
+		"duration":    time.Since(start).String(),
+	})
+	resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// redactedValue replaces the values of sensitive fields in logged bodies.
+const redactedValue = "REDACTED"
+
+// isSensitiveKey returns whether the values of a JSON field with the given name
+// must not be logged, such as credentials and secrets.
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range []string{"secret", "password", "credentials", "apikey", "api_key", "accesstoken", "access_token", "refreshtoken", "refresh_token", "authorization"} {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactedBody is a request or response body logged with its sensitive
+// fields redacted. The redaction only runs when the logger formats the field,
+// so bodies aren't decoded unless trace logging is enabled.
+type redactedBody []byte
+
+func (b redactedBody) String() string {
+	return redactBody(b)
+}
+
+func (b redactedBody) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redactBody(b))
+}
+
+// redactBody returns a JSON request or response body for logging, with the
+// values of sensitive fields replaced. Bodies that aren't JSON are not logged,
+// as they can't be redacted.
+func redactBody(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return fmt.Sprintf("<%d bytes of non-JSON body>", len(body))
+	}
+
+	var redact func(v any) any
+	redact = func(v any) any {
+		switch v := v.(type) {
+		case map[string]any:
+			for k, e := range v {
+				if isSensitiveKey(k) {
+					v[k] = redactedValue
+				} else {
+					v[k] = redact(e)
+				}
+			}
+		case []any:
+			for i, e := range v {
+				v[i] = redact(e)
+			}
+		}
+		return v
+	}
+
+	redacted, err := json.Marshal(redact(decoded))
+	if err != nil {
+		return fmt.Sprintf("<%d bytes of body>", len(body))
+	}
+	return string(redacted)
+}
+
+// cancelOnClose is a response body which cancels the context of its request
+// when closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// list fetches every page of a list endpoint, calling fn with each item in
+// turn. Iteration stops early if fn returns false.
+func (c *m3terClient) list(ctx context.Context, path string, query url.Values, fn func(map[string]any) bool) error {
+	queryParams := make(url.Values)
+	for k, v := range query {
+		queryParams[k] = v
+	}
+	if queryParams.Get("pageSize") == "" {
+		queryParams.Set("pageSize", "200")
+	}
+
+	for {
+		var response struct {
+			Data      []map[string]any `json:"data"`
+			NextToken string           `json:"nextToken"`
+		}
+		err := c.execute(ctx, "GET", path, queryParams, nil, &response)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range response.Data {
+			if !fn(item) {
+				return nil
+			}
+		}
+
+		if response.NextToken == "" {
+			return nil
+		}
+
+		queryParams.Set("nextToken", response.NextToken)
+	}
+}
+
+// discoverOrganizationID returns the ID of the only organization the
+// credentials have access to, failing if there are none or several.
+func (c *m3terClient) discoverOrganizationID(ctx context.Context) (string, error) {
+	var ids []string
+	query := url.Values{}
+	query.Set("pageSize", "200")
+	for {
+		var response struct {
+			Data []struct {
+				Id string `json:"id"`
+			} `json:"data"`
+			NextToken string `json:"nextToken"`
+		}
+		err := c.executeRaw(ctx, "GET", "/organizations", query, nil, &response)
+		if err != nil {
+			return "", err
+		}
+
+		for _, org := range response.Data {
+			ids = append(ids, org.Id)
+		}
+
+		if response.NextToken == "" {
+			break
+		}
+
+		query.Set("nextToken", response.NextToken)
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("the credentials do not have access to any organization")
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("the credentials have access to %d organizations, so the organization ID must be set", len(ids))
+	}
+}
+
+type apiCallsKey struct{}
+
+// countAPICalls returns a context in which the requests sent by the client
+// are counted, and a function logging the count at debug level once the
+// operation is done.
+func countAPICalls(ctx context.Context, operation, name string) (context.Context, func()) {
+	calls := new(atomic.Int64)
+	ctx = context.WithValue(ctx, apiCallsKey{}, calls)
+	return ctx, func() {
+		tflog.Debug(ctx, "m3ter API calls made", map[string]any{
+			"operation": operation,
+			"entity":    name,
+			"calls":     calls.Load(),
+		})
+	}
+}
+
 type statusCodeError struct {
 	StatusCode int
 	Body       string
+	// RequestID identifies the request to m3ter support, or is empty when
+	// the response didn't include one
+	RequestID string
 }
 
 func (e *statusCodeError) Error() string {
+	msg := fmt.Sprintf("unexpected status code %d", e.StatusCode)
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" (request ID %s)", e.RequestID)
+	}
 	if e.Body == "" {
-		return fmt.Sprintf("unexpected status code %d", e.StatusCode)
+		return msg
+	}
+	return msg + ": " + e.Body
+}
+
+// requestIDHeaders are the response headers which may identify a request, in
+// order of preference.
+var requestIDHeaders = []string{"X-M3ter-Request-Id", "X-Request-Id", "X-Amzn-Requestid"}
+
+// responseRequestID returns the ID m3ter assigned to the request of a
+// response, or an empty string if there is none.
+func responseRequestID(resp *http.Response) string {
+	for _, header := range requestIDHeaders {
+		if id := resp.Header.Get(header); id != "" {
+			return id
+		}
 	}
-	return fmt.Sprintf("unexpected status code %d: %s", e.StatusCode, e.Body)
+	return ""
+}
+
+// isConflict reports whether err is a 409 Conflict response, which the API
+// returns when an entity can't be changed or deleted because of the state of
+// other entities.
+func isConflict(err error) bool {
+	sc, ok := err.(*statusCodeError)
+	return ok && sc.StatusCode == http.StatusConflict
 }
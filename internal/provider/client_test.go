@@ -0,0 +1,178 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/time/rate"
+)
+
+// newTestClient returns a client of the fake API, retrying after about a
+// millisecond.
+func newTestClient(t *testing.T, api *fakeAPI) *m3terClient {
+	t.Helper()
+
+	credentials := &clientcredentials.Config{
+		ClientID:     "access",
+		ClientSecret: "secret",
+		TokenURL:     api.server.URL + "/oauth/token",
+	}
+	c := newM3terClient(api.server.URL, testOrganizationID, credentials, rate.NewLimiter(rate.Inf, 1), rate.NewLimiter(rate.Inf, 1))
+	c.setRootCAs(api.server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs)
+	c.retryBaseDelay = time.Millisecond
+	return c
+}
+
+func TestClientListLargeCollection(t *testing.T) {
+	api := newFakeAPI(t)
+	for i := 0; i < 5000; i++ {
+		api.put("meters", map[string]any{"code": fmt.Sprintf("meter_%04d", i)})
+	}
+	c := newTestClient(t, api)
+
+	var seen int
+	err := c.list(context.Background(), "/meters", nil, func(map[string]any) bool {
+		seen++
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seen != 5000 {
+		t.Errorf("listed %d meters, want 5000", seen)
+	}
+	if pages := len(api.requestsTo(http.MethodGet, "/meters")); pages != 25 {
+		t.Errorf("fetched %d pages, want 25", pages)
+	}
+
+	// Stopping part way through the second page fetches no further pages
+	api.clearRequests()
+	seen = 0
+	err = c.list(context.Background(), "/meters", nil, func(map[string]any) bool {
+		seen++
+		return seen < 300
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pages := len(api.requestsTo(http.MethodGet, "/meters")); pages != 2 {
+		t.Errorf("fetched %d pages, want 2", pages)
+	}
+}
+
+func TestClientExecuteBoundsErrorBody(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle(func(w http.ResponseWriter, req *fakeRequest) bool {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(strings.Repeat("x", 10*maxErrorBodySize)))
+		return true
+	})
+	c := newTestClient(t, api)
+
+	err := c.execute(context.Background(), http.MethodGet, "/meters", nil, nil, nil)
+	var statusErr *statusCodeError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("got error %v, want a status code error", err)
+	}
+	if statusErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("status code = %d, want %d", statusErr.StatusCode, http.StatusBadRequest)
+	}
+	if len(statusErr.Body) != maxErrorBodySize {
+		t.Errorf("read %d bytes of the error body, want %d", len(statusErr.Body), maxErrorBodySize)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		min    time.Duration
+		max    time.Duration
+	}{
+		{"seconds", "3", 3 * time.Second, 3 * time.Second},
+		{"future date", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), 58 * time.Second, time.Minute},
+		{"past date", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 0, 0},
+		{"RFC1123 date", time.Now().Add(time.Minute).UTC().Format(time.RFC1123), 58 * time.Second, time.Minute},
+		{"negative seconds", "-1", 0, 0},
+		{"invalid", "soon", 0, 0},
+		{"missing", "", 0, 0},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{Header: http.Header{}}
+		if tt.header != "" {
+			resp.Header.Set("Retry-After", tt.header)
+		}
+		if got := parseRetryAfter(resp); got < tt.min || got > tt.max {
+			t.Errorf("%s: parseRetryAfter(%q) = %s, want between %s and %s", tt.name, tt.header, got, tt.min, tt.max)
+		}
+	}
+}
+
+func TestClientExecuteRetryAfter(t *testing.T) {
+	api := newFakeAPI(t)
+	api.put("products", map[string]any{"code": "product"})
+	limited := true
+	api.handle(func(w http.ResponseWriter, req *fakeRequest) bool {
+		if !limited {
+			return false
+		}
+		limited = false
+		w.Header().Set("Retry-After", "0")
+		writeJSON(w, http.StatusTooManyRequests, map[string]any{"message": "rate limited"})
+		return true
+	})
+	c := newTestClient(t, api)
+
+	var found int
+	err := c.list(context.Background(), "/products", nil, func(map[string]any) bool {
+		found++
+		return true
+	})
+	if err != nil || found != 1 {
+		t.Errorf("list got %d products and error %v, want the rate limited request retried", found, err)
+	}
+}
+
+func TestClientExecuteRedactsLoggedBodies(t *testing.T) {
+	api := newFakeAPI(t)
+	api.handle(func(w http.ResponseWriter, req *fakeRequest) bool {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"name":   "webhook",
+			"config": map[string]any{"type": "M3TER_SIGNED_REQUEST", "secret": "response-s3cr3t"},
+		})
+		return true
+	})
+	c := newTestClient(t, api)
+
+	var logged bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &logged)
+
+	requestBody := map[string]any{
+		"name":   "webhook",
+		"apiKey": "request-s3cr3t",
+		"config": []any{map[string]any{"password": "request-s3cr3t"}},
+	}
+	var responseBody map[string]any
+	if err := c.execute(ctx, http.MethodPost, "/integrationdestinations/webhooks", nil, requestBody, &responseBody); err != nil {
+		t.Fatalf("execute failed: %s", err)
+	}
+
+	output := logged.String()
+	if strings.Contains(output, "s3cr3t") {
+		t.Errorf("a secret was logged:\n%s", output)
+	}
+	if !strings.Contains(output, redactedValue) || !strings.Contains(output, "M3TER_SIGNED_REQUEST") {
+		t.Errorf("the redacted bodies weren't logged:\n%s", output)
+	}
+}
@@ -0,0 +1,302 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &CommitmentResource{}
+var _ resource.ResourceWithImportState = &CommitmentResource{}
+var _ resource.ResourceWithModifyPlan = &CommitmentResource{}
+
+func NewCommitmentResource() resource.Resource {
+	r := &CommitmentResource{}
+	r.genericResource = genericResource[CommitmentResourceModel, *CommitmentResourceModel]{
+		typeName: "commitment",
+		path:     "/commitments",
+		name:     "commitment",
+		read:     r.read,
+		write:    r.write,
+	}
+	return r
+}
+
+// CommitmentResource defines the resource implementation.
+type CommitmentResource struct {
+	genericResource[CommitmentResourceModel, *CommitmentResourceModel]
+}
+
+// CommitmentResourceModel describes the resource data model.
+type CommitmentResourceModel struct {
+	AccountId                          types.String   `tfsdk:"account_id"`
+	Amount                             types.Float64  `tfsdk:"amount"`
+	Currency                           types.String   `tfsdk:"currency"`
+	StartDate                          types.String   `tfsdk:"start_date"`
+	EndDate                            types.String   `tfsdk:"end_date"`
+	BillEpoch                          types.String   `tfsdk:"bill_epoch"`
+	AmountPrePaid                      types.Float64  `tfsdk:"amount_pre_paid"`
+	FeeDates                           types.List     `tfsdk:"fee_dates"`
+	CommitmentFeeBillInAdvance         types.Bool     `tfsdk:"commitment_fee_bill_in_advance"`
+	CommitmentUsageAccountingProductId types.String   `tfsdk:"commitment_usage_accounting_product_id"`
+	DrawdownsAccountingProductId       types.String   `tfsdk:"drawdowns_accounting_product_id"`
+	LineItemTypes                      types.List     `tfsdk:"line_item_types"`
+	CustomFields                       types.Dynamic  `tfsdk:"custom_fields"`
+	Id                                 types.String   `tfsdk:"id"`
+	Version                            types.Int64    `tfsdk:"version"`
+	Timeouts                           timeouts.Value `tfsdk:"timeouts"`
+}
+
+var commitmentFeeDateType = schema.NestedAttributeObject{
+	Attributes: map[string]schema.Attribute{
+		"date": schema.StringAttribute{
+			MarkdownDescription: "The date (in ISO-8601 format) the fee is billed.",
+			Required:            true,
+		},
+		"amount": schema.Float64Attribute{
+			MarkdownDescription: "The amount of the fee.",
+			Required:            true,
+		},
+		"service_period_start_date": schema.StringAttribute{
+			MarkdownDescription: "The start date (in ISO-8601 format) of the service period the fee covers.",
+			Optional:            true,
+		},
+		"service_period_end_date": schema.StringAttribute{
+			MarkdownDescription: "The end date (in ISO-8601 format) of the service period the fee covers.",
+			Optional:            true,
+		},
+	},
+}
+
+// commitmentFeeDateFields maps the fee date attribute names to the API field names.
+var commitmentFeeDateFields = map[string]string{
+	"date":                      "date",
+	"amount":                    "amount",
+	"service_period_start_date": "servicePeriodStartDate",
+	"service_period_end_date":   "servicePeriodEndDate",
+}
+
+func (r *CommitmentResourceModel) GetId() types.String {
+	return r.Id
+}
+
+func (r *CommitmentResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Commitment resource",
+
+		Attributes: map[string]schema.Attribute{
+			"account_id": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the Account the Commitment is for.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"amount": schema.Float64Attribute{
+				MarkdownDescription: "The total amount the customer has committed to pay.",
+				Required:            true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+			"currency": schema.StringAttribute{
+				MarkdownDescription: "The currency of the Commitment, such as USD or GBP.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(3, 3),
+				},
+			},
+			"start_date": schema.StringAttribute{
+				MarkdownDescription: "The start date (in ISO-8601 format) of the Commitment period.",
+				Required:            true,
+				Validators: []validator.String{
+					iso8601(),
+				},
+			},
+			"end_date": schema.StringAttribute{
+				MarkdownDescription: "The end date (in ISO-8601 format) of the Commitment period.",
+				Required:            true,
+				Validators: []validator.String{
+					iso8601(),
+				},
+			},
+			"bill_epoch": schema.StringAttribute{
+				MarkdownDescription: "The starting date (in ISO-8601 format) from which the billing cycles are calculated.",
+				Optional:            true,
+			},
+			"amount_pre_paid": schema.Float64Attribute{
+				MarkdownDescription: "The amount of the Commitment the customer has paid in advance.",
+				Optional:            true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+			"fee_dates": schema.ListNestedAttribute{
+				MarkdownDescription: "The dates and amounts of the Commitment fees, for Commitments billed on a schedule.",
+				Optional:            true,
+				NestedObject:        commitmentFeeDateType,
+			},
+			"commitment_fee_bill_in_advance": schema.BoolAttribute{
+				MarkdownDescription: "Whether the Commitment fees are billed in advance.",
+				Optional:            true,
+			},
+			"commitment_usage_accounting_product_id": schema.StringAttribute{
+				MarkdownDescription: "Optional Product ID the Commitment usage should be attributed to for accounting purposes.",
+				Optional:            true,
+			},
+			"drawdowns_accounting_product_id": schema.StringAttribute{
+				MarkdownDescription: "Optional Product ID the Commitment drawdowns should be attributed to for accounting purposes.",
+				Optional:            true,
+			},
+			"line_item_types": schema.ListAttribute{
+				MarkdownDescription: "The types of Bill line items the Commitment can be drawn down against.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"custom_fields": schema.DynamicAttribute{
+				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be a string, a number or a boolean.",
+				Required:            true,
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The UUID of the entity.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"version": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The version number.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
+	}
+}
+
+func (r *CommitmentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkRequiredCustomFields(ctx, r.client, req.Plan, &resp.Diagnostics)
+}
+
+func (r *CommitmentResource) read(ctx context.Context, data *CommitmentResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           restData,
+	}
+	m.to("id", &data.Id)
+	m.to("version", &data.Version)
+	m.to("accountId", &data.AccountId)
+	m.to("amount", &data.Amount)
+	m.to("currency", &data.Currency)
+	m.to("startDate", &data.StartDate)
+	m.to("endDate", &data.EndDate)
+	m.to("billEpoch", &data.BillEpoch)
+	m.to("amountPrePaid", &data.AmountPrePaid)
+	m.to("commitmentFeeBillInAdvance", &data.CommitmentFeeBillInAdvance)
+	m.to("commitmentUsageAccountingProductId", &data.CommitmentUsageAccountingProductId)
+	m.to("drawdownsAccountingProductId", &data.DrawdownsAccountingProductId)
+	m.customFieldsTo(&data.CustomFields)
+
+	m.listTo("lineItemTypes", &data.LineItemTypes, types.StringType, func(v any) (attr.Value, diag.Diagnostics) {
+		if s, ok := v.(string); ok {
+			return types.StringValue(s), nil
+		}
+
+		return nil, diag.Diagnostics{diag.NewErrorDiagnostic("cannot map list element, expected string", "")}
+	})
+
+	m.listTo("feeDates", &data.FeeDates, commitmentFeeDateType.Type(), func(v any) (attr.Value, diag.Diagnostics) {
+		mv, ok := v.(map[string]any)
+		if !ok {
+			return nil, diag.Diagnostics{diag.NewErrorDiagnostic("cannot map list element, expected map", "")}
+		}
+
+		attrs := make(map[string]attr.Value)
+		ts := make(map[string]attr.Type)
+		for k, field := range commitmentFeeDateFields {
+			ts[k] = commitmentFeeDateType.Attributes[k].GetType()
+			switch v := mv[field].(type) {
+			case string:
+				attrs[k] = types.StringValue(v)
+			case float64:
+				attrs[k] = types.Float64Value(v)
+			default:
+				if ts[k].Equal(types.Float64Type) {
+					attrs[k] = types.Float64Null()
+				} else {
+					attrs[k] = types.StringNull()
+				}
+			}
+		}
+
+		return types.ObjectValue(ts, attrs)
+	})
+}
+
+func (r *CommitmentResource) write(ctx context.Context, data *CommitmentResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           restData,
+	}
+	m.from(data.Id, "id")
+	m.from(data.Version, "version")
+	m.from(data.AccountId, "accountId")
+	m.from(data.Amount, "amount")
+	m.from(data.Currency, "currency")
+	m.from(data.StartDate, "startDate")
+	m.from(data.EndDate, "endDate")
+	m.from(data.BillEpoch, "billEpoch")
+	m.from(data.AmountPrePaid, "amountPrePaid")
+	m.from(data.CommitmentFeeBillInAdvance, "commitmentFeeBillInAdvance")
+	m.from(data.CommitmentUsageAccountingProductId, "commitmentUsageAccountingProductId")
+	m.from(data.DrawdownsAccountingProductId, "drawdownsAccountingProductId")
+	m.customFieldsFrom(data.CustomFields)
+
+	m.listFrom(data.LineItemTypes, "lineItemTypes", func(v attr.Value) (any, diag.Diagnostics) {
+		if sv, ok := v.(types.String); ok {
+			return sv.ValueString(), nil
+		}
+
+		return nil, diag.Diagnostics{diag.NewErrorDiagnostic("cannot map list element, expected string", "")}
+	})
+
+	m.listFrom(data.FeeDates, "feeDates", func(v attr.Value) (any, diag.Diagnostics) {
+		ov, ok := v.(types.Object)
+		if !ok {
+			return nil, diag.Diagnostics{diag.NewErrorDiagnostic("cannot map list element, expected object", "")}
+		}
+
+		var diags diag.Diagnostics
+		feeDate := make(map[string]any)
+		fm := &mapper{
+			ctx:         ctx,
+			diagnostics: &diags,
+			v:           feeDate,
+		}
+		attrs := ov.Attributes()
+		for k, field := range commitmentFeeDateFields {
+			if v, ok := attrs[k].(unknowable); ok {
+				fm.from(v, field)
+			}
+		}
+
+		return feeDate, diags
+	})
+}
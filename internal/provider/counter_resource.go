@@ -6,9 +6,9 @@ package provider
 import (
 	"context"
 	"fmt"
-	"net/url"
 	"regexp"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -35,12 +35,14 @@ type CounterResource struct {
 
 // CounterResourceModel describes the resource data model.
 type CounterResourceModel struct {
-	Code      types.String `tfsdk:"code"`
-	ProductId types.String `tfsdk:"product_id"`
-	Name      types.String `tfsdk:"name"`
-	Unit      types.String `tfsdk:"unit"`
-	Id        types.String `tfsdk:"id"`
-	Version   types.Int64  `tfsdk:"version"`
+	Code        types.String   `tfsdk:"code"`
+	ProductId   types.String   `tfsdk:"product_id"`
+	ProductCode types.String   `tfsdk:"product_code"`
+	Name        types.String   `tfsdk:"name"`
+	Unit        types.String   `tfsdk:"unit"`
+	Id          types.String   `tfsdk:"id"`
+	Version     types.Int64    `tfsdk:"version"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *CounterResourceModel) GetId() types.String {
@@ -59,6 +61,18 @@ func (r *CounterResource) Schema(ctx context.Context, req resource.SchemaRequest
 			"product_id": schema.StringAttribute{
 				MarkdownDescription: "UUID of the product the Counter belongs to. (Optional) - if left blank, the Counter is global.",
 				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					productIdFromCode(),
+				},
+			},
+			"product_code": schema.StringAttribute{
+				MarkdownDescription: "Code of the product the Counter belongs to, resolved to `product_id` at apply time. Conflicts with `product_id`. Neither is set for a global Counter, which belongs to no product.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("product_id")),
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
 			"name": schema.StringAttribute{
 				MarkdownDescription: "Descriptive name for the Counter.",
@@ -68,8 +82,12 @@ func (r *CounterResource) Schema(ctx context.Context, req resource.SchemaRequest
 				},
 			},
 			"code": schema.StringAttribute{
-				MarkdownDescription: "Code of the Counter - unique short code used to identify the Counter.",
+				MarkdownDescription: "Code of the Counter - unique short code used to identify the Counter. Generated by m3ter when left blank.",
 				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 				Validators: []validator.String{
 					stringvalidator.LengthBetween(1, 80),
 					stringvalidator.RegexMatches(regexp.MustCompile(`^([^\p{Cc}\s])|([^\p{Cc}\s][[^\p{Cc}\s] ]*[^\p{Cc}\s])$`), "The code must not contain control characters or start/end with whitespace."),
@@ -94,6 +112,9 @@ func (r *CounterResource) Schema(ctx context.Context, req resource.SchemaRequest
 				MarkdownDescription: "Counter version",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -134,35 +155,7 @@ func (r *CounterResource) Delete(ctx context.Context, req resource.DeleteRequest
 }
 
 func (r *CounterResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	var restData map[string]any
-	err := r.client.execute(ctx, "GET", "/counters/"+url.PathEscape(req.ID), nil, nil, &restData)
-	if sc, ok := err.(*statusCodeError); ok && sc.StatusCode == 404 {
-		urlValues := url.Values{}
-		urlValues.Set("pageSize", "1")
-		urlValues.Set("codes", req.ID)
-
-		var counterListResponse struct {
-			Data []struct {
-				Id      string `json:"id"`
-				Code    string `json:"code"`
-				Version int64  `json:"version"`
-			} `json:"data"`
-			NextToken string `json:"next_token"`
-		}
-		err := r.client.execute(ctx, "GET", "/counters", nil, nil, &counterListResponse)
-		if err != nil {
-			resp.Diagnostics.AddError("Failed to list counters", err.Error())
-			return
-		}
-		for _, counter := range counterListResponse.Data {
-			if counter.Code == req.ID {
-				resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), counter.Id)...)
-				return
-			}
-		}
-		resp.Diagnostics.AddError("Counter not found", "The counter with code "+req.ID+" does not exist.")
-	}
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	genericImportState(ctx, req, resp, r.client, "/counters", "counter", []string{"code"})
 }
 
 func (r *CounterResource) read(ctx context.Context, data *CounterResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
@@ -174,8 +167,15 @@ func (r *CounterResource) read(ctx context.Context, data *CounterResourceModel,
 	m.to("id", &data.Id)
 	m.to("version", &data.Version)
 	m.to("productId", &data.ProductId)
+	// The API omits the product of global entities, or leaves it empty
+	if data.ProductId.IsUnknown() || data.ProductId.ValueString() == "" {
+		data.ProductId = types.StringNull()
+	}
 	m.to("name", &data.Name)
 	m.to("code", &data.Code)
+	if data.Code.IsUnknown() {
+		data.Code = types.StringNull()
+	}
 	m.to("unit", &data.Unit)
 }
 
@@ -189,6 +189,12 @@ func (r *CounterResource) write(ctx context.Context, data *CounterResourceModel,
 	m.from(data.Id, "id")
 	m.from(data.Version, "version")
 	m.from(data.ProductId, "productId")
+	// A global entity is planned with a null product, which clears any
+	// product it was moved from
+	if data.ProductId.IsNull() {
+		delete(m.v, "productId")
+	}
+	resolveProductCode(ctx, r.client, data.ProductCode, restData, diagnostics)
 	m.from(data.Name, "name")
 	m.from(data.Code, "code")
 	m.from(data.Unit, "unit")
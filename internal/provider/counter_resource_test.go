@@ -0,0 +1,63 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+)
+
+func TestCounterResourceImportGeneratedCode(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	id := api.put("counters", map[string]any{
+		"name":      "Seats",
+		"code":      "seats_1a2b3c",
+		"unit":      "seat",
+		"productId": "",
+	})
+
+	state := p.importState("m3ter_counter", id)
+	if v := attrValue(t, state, "code"); v != "seats_1a2b3c" {
+		t.Errorf("code = %v, want the generated seats_1a2b3c", v)
+	}
+	if v := attrValue(t, state, "product_id"); v != nil {
+		t.Errorf("product_id = %v, want null for a global counter", v)
+	}
+	p.assertNoChanges("m3ter_counter", state, map[string]any{
+		"name": "Seats",
+		"unit": "seat",
+	})
+}
+
+func TestCounterResourceGlobal(t *testing.T) {
+	api := newFakeAPI(t)
+	// The API returns an empty product for global counters
+	api.onWrite = func(collection string, entity map[string]any) {
+		if _, ok := entity["productId"]; collection == "counters" && !ok {
+			entity["productId"] = ""
+		}
+	}
+	p := newTestProvider(t, api, nil)
+
+	config := map[string]any{
+		"name": "Seats",
+		"code": "seats",
+		"unit": "seat",
+	}
+	state := p.create("m3ter_counter", config)
+	if v := attrValue(t, state, "product_id"); v != nil {
+		t.Errorf("product_id = %v, want null", v)
+	}
+
+	config["name"] = "Licensed seats"
+	planned, diags := p.plan("m3ter_counter", state, config)
+	p.checkDiagnostics("plan", diags)
+	if v := attrValue(t, planned, "product_id"); v != nil {
+		t.Errorf("planned product_id = %v, want null", v)
+	}
+	state = p.update("m3ter_counter", state, config)
+	state = p.read("m3ter_counter", state)
+	p.assertNoChanges("m3ter_counter", state, config)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func NewCurrencyResource() resource.Resource {
+	return newPicklistResource[CurrencyResourceModel]("currency", "/picklists/currency", "currency", "Currency resource, a currency enabled for the Organization.", currencyAttributes, readCurrency, writeCurrency)
+}
+
+// CurrencyResourceModel describes the resource data model.
+type CurrencyResourceModel struct {
+	PicklistResourceModel
+
+	MaxDecimalPlaces types.Int32  `tfsdk:"max_decimal_places"`
+	RoundingMode     types.String `tfsdk:"rounding_mode"`
+}
+
+// currencyAttributes are the attributes of currencies besides those common to
+// every picklist. The API defaults both when they aren't set.
+var currencyAttributes = map[string]schema.Attribute{
+	"max_decimal_places": schema.Int32Attribute{
+		MarkdownDescription: "The maximum number of decimal places amounts in the currency are shown with on Bills. Defaulted by m3ter when left blank.",
+		Optional:            true,
+		Computed:            true,
+		PlanModifiers: []planmodifier.Int32{
+			int32planmodifier.UseStateForUnknown(),
+		},
+		Validators: []validator.Int32{
+			int32validator.Between(0, 10),
+		},
+	},
+	"rounding_mode": schema.StringAttribute{
+		MarkdownDescription: "How amounts in the currency are rounded to the maximum number of decimal places. One of UP, DOWN, CEILING, FLOOR, HALF_UP, HALF_DOWN, HALF_EVEN or UNNECESSARY. Defaulted by m3ter when left blank.",
+		Optional:            true,
+		Computed:            true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.UseStateForUnknown(),
+		},
+		Validators: []validator.String{
+			stringvalidator.OneOf("UP", "DOWN", "CEILING", "FLOOR", "HALF_UP", "HALF_DOWN", "HALF_EVEN", "UNNECESSARY"),
+		},
+	},
+}
+
+func readCurrency(m *mapper, data *CurrencyResourceModel) {
+	m.to("maxDecimalPlaces", &data.MaxDecimalPlaces)
+	if data.MaxDecimalPlaces.IsUnknown() {
+		data.MaxDecimalPlaces = types.Int32Null()
+	}
+	m.to("roundingMode", &data.RoundingMode)
+	if data.RoundingMode.IsUnknown() {
+		data.RoundingMode = types.StringNull()
+	}
+}
+
+func writeCurrency(m *mapper, data *CurrencyResourceModel) {
+	m.from(data.MaxDecimalPlaces, "maxDecimalPlaces")
+	m.from(data.RoundingMode, "roundingMode")
+}
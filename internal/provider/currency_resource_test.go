@@ -0,0 +1,46 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+)
+
+func TestCurrencyResourceServerDefaults(t *testing.T) {
+	api := newFakeAPI(t)
+	// The API defaults the rounding of currencies created without one
+	api.onWrite = func(collection string, entity map[string]any) {
+		if collection != "picklists/currency" {
+			return
+		}
+		if _, ok := entity["maxDecimalPlaces"]; !ok {
+			entity["maxDecimalPlaces"] = float64(2)
+		}
+		if _, ok := entity["roundingMode"]; !ok {
+			entity["roundingMode"] = "HALF_UP"
+		}
+	}
+	p := newTestProvider(t, api, nil)
+
+	config := map[string]any{"name": "EUR", "code": "EUR"}
+	state := p.create("m3ter_currency", config)
+	if v := attrValue(t, state, "max_decimal_places"); v != float64(2) {
+		t.Errorf("max_decimal_places = %v, want the defaulted 2", v)
+	}
+	if v := attrValue(t, state, "rounding_mode"); v != "HALF_UP" {
+		t.Errorf("rounding_mode = %v, want the defaulted HALF_UP", v)
+	}
+	p.assertNoChanges("m3ter_currency", state, config)
+
+	config["rounding_mode"] = "HALF_EVEN"
+	state = p.update("m3ter_currency", state, config)
+	if v := attrValue(t, state, "max_decimal_places"); v != float64(2) {
+		t.Errorf("max_decimal_places = %v after update, want 2 kept", v)
+	}
+	state = p.read("m3ter_currency", state)
+	p.assertNoChanges("m3ter_currency", state, config)
+
+	imported := p.importState("m3ter_currency", "EUR")
+	p.assertNoChanges("m3ter_currency", imported, config)
+}
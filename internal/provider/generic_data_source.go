@@ -0,0 +1,286 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// extraQueryAttribute lets list-style data sources pass query parameters the
+// provider doesn't model, such as newly added API filters.
+var extraQueryAttribute = schema.MapAttribute{
+	MarkdownDescription: "Additional query parameters sent when listing entities, to use API filters the data source does not support yet.",
+	ElementType:         types.StringType,
+	Optional:            true,
+}
+
+// mergeExtraQuery returns query with the parameters of extraQuery added,
+// replacing any of the same name.
+func mergeExtraQuery(ctx context.Context, extraQuery types.Map, query url.Values, diagnostics *diag.Diagnostics) url.Values {
+	if extraQuery.IsNull() || extraQuery.IsUnknown() {
+		return query
+	}
+
+	var params map[string]string
+	diagnostics.Append(extraQuery.ElementsAs(ctx, &params, false)...)
+	if query == nil {
+		query = url.Values{}
+	}
+	for k, v := range params {
+		query.Set(k, v)
+	}
+	return query
+}
+
+// genericDataSource implements a data source that looks up a single entity,
+// either by its id or by matching filter fields against the entities listed
+// at path. Data sources embed it and provide the schema.
+type genericDataSource[T any, PT idable[T]] struct {
+	client *m3terClient
+
+	// typeName is the data source type name, without the provider prefix
+	typeName string
+	// path is the API path the entities are listed at
+	path string
+	// name is the human readable entity name used in diagnostics
+	name string
+	// filters returns the filter values from the config, keyed by API field
+	filters func(PT) map[string]types.String
+	// extraQuery returns the extra_query value from the config
+	extraQuery func(PT) types.Map
+	// read maps an API entity into the data source model
+	read func(context.Context, PT, map[string]any, *diag.Diagnostics)
+}
+
+func (r *genericDataSource[T, PT]) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + r.typeName
+}
+
+func (r *genericDataSource[T, PT]) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*m3terClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *m3terClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *genericDataSource[T, PT]) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, logCalls := countAPICalls(ctx, "read", r.name)
+	defer logCalls()
+
+	var data T
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if id := PT(&data).GetId(); !id.IsUnknown() && !id.IsNull() {
+		var restData map[string]any
+		err := r.client.execute(ctx, "GET", r.path+"/"+url.PathEscape(id.ValueString()), nil, nil, &restData)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read %s, got error: %s", r.name, err))
+			return
+		}
+
+		r.read(ctx, &data, restData, &resp.Diagnostics)
+
+		// Save updated data into Terraform state
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	filters := r.filters(&data)
+	query := mergeExtraQuery(ctx, r.extraQuery(&data), nil, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var matches []map[string]any
+	err := r.client.cachedList(ctx, r.path, query, func(restData map[string]any) bool {
+		for field, filter := range filters {
+			if filter.IsUnknown() || filter.IsNull() {
+				continue
+			}
+
+			value, ok := restData[field].(string)
+			if !ok || value != filter.ValueString() {
+				return true
+			}
+		}
+
+		matches = append(matches, restData)
+		// Stop once we know the criteria are ambiguous.
+		return len(matches) < 2
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list %ss, got error: %s", r.name, err))
+		return
+	}
+
+	if len(matches) == 0 {
+		resp.Diagnostics.AddError(fmt.Sprintf("No matching %s found", r.name), fmt.Sprintf("No %s found matching the specified criteria", r.name))
+		return
+	}
+
+	if len(matches) > 1 {
+		resp.Diagnostics.AddError(fmt.Sprintf("Multiple matching %ss found", r.name), fmt.Sprintf("Multiple %ss found matching the specified criteria", r.name))
+		return
+	}
+
+	r.read(ctx, &data, matches[0], &resp.Diagnostics)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// genericListDataSource implements a data source listing the entities at
+// path as a list attribute of entries, ordered by one of their fields. Data
+// sources embed it and provide the schema, with the entry attributes of
+// entryAttributes.
+type genericListDataSource[T any, E any] struct {
+	client *m3terClient
+
+	// typeName is the data source type name, without the provider prefix
+	typeName string
+	// path is the API path the entities are listed at
+	path string
+	// name is the human readable entity name used in diagnostics
+	name string
+	// orderBy is the API field the entries are ordered by
+	orderBy string
+	// entryAttributes are the attributes of the entries
+	entryAttributes map[string]schema.Attribute
+	// query, when set, returns the query parameters filtering the list
+	query func(context.Context, *T, *diag.Diagnostics) url.Values
+	// match, when set, reports whether a listed entity is included, checking
+	// the filters of query in case the API ignores them
+	match func(*T, map[string]any) bool
+	// extraQuery returns the extra_query value from the config
+	extraQuery func(*T) types.Map
+	// entries returns the list attribute of the model holding the entries
+	entries func(*T) *types.List
+	// read maps an API entity into an entry
+	read func(context.Context, *E, map[string]any, *diag.Diagnostics)
+	// done, when set, is called with the entities found before they are
+	// stored, to check them or to fill other attributes
+	done func(context.Context, *T, []map[string]any, *diag.Diagnostics)
+}
+
+func (r *genericListDataSource[T, E]) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + r.typeName
+}
+
+func (r *genericListDataSource[T, E]) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*m3terClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *m3terClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *genericListDataSource[T, E]) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, logCalls := countAPICalls(ctx, "read", r.name)
+	defer logCalls()
+
+	var data T
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var query url.Values
+	if r.query != nil {
+		query = r.query(ctx, &data, &resp.Diagnostics)
+	}
+	query = mergeExtraQuery(ctx, r.extraQuery(&data), query, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var found []map[string]any
+	err := r.client.list(ctx, r.path, query, func(restData map[string]any) bool {
+		if r.match == nil || r.match(&data, restData) {
+			found = append(found, restData)
+		}
+		return true
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list %ss, got error: %s", r.name, err))
+		return
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		a, _ := found[i][r.orderBy].(string)
+		b, _ := found[j][r.orderBy].(string)
+		return a < b
+	})
+
+	if r.done != nil {
+		r.done(ctx, &data, found, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	entryTypes := make(map[string]attr.Type)
+	for k, v := range r.entryAttributes {
+		entryTypes[k] = v.GetType()
+	}
+
+	entries := make([]attr.Value, 0, len(found))
+	for _, restData := range found {
+		var entry E
+		r.read(ctx, &entry, restData, &resp.Diagnostics)
+
+		ov, diag := types.ObjectValueFrom(ctx, entryTypes, entry)
+		resp.Diagnostics.Append(diag...)
+		entries = append(entries, ov)
+	}
+
+	lv, diag := types.ListValue(types.ObjectType{AttrTypes: entryTypes}, entries)
+	resp.Diagnostics.Append(diag...)
+	*r.entries(&data) = lv
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
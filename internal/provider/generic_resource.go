@@ -0,0 +1,135 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// genericResource implements the behavior common to resources backed by a
+// standard m3ter CRUD endpoint. Resources embed it and provide the schema,
+// overriding any of the other methods where they need to.
+type genericResource[T any, PT idable[T]] struct {
+	client *m3terClient
+
+	// typeName is the resource type name, without the provider prefix
+	typeName string
+	// path is the API path the entities are managed at
+	path string
+	// name is the human readable entity name used in diagnostics
+	name string
+	// importFields, when set, are the API fields matched against import IDs
+	// that are not UUIDs, allowing import by e.g. code or name
+	importFields []string
+	// read maps an API entity into the resource model
+	read func(context.Context, *T, map[string]any, *diag.Diagnostics)
+	// write maps the resource model into an API entity
+	write func(context.Context, *T, map[string]any, *diag.Diagnostics)
+}
+
+func (r *genericResource[T, PT]) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + r.typeName
+}
+
+func (r *genericResource[T, PT]) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*m3terClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *m3terClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *genericResource[T, PT]) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	genericCreate[T](ctx, req, resp, r.client, r.path, r.name, r.read, r.write)
+}
+
+func (r *genericResource[T, PT]) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	genericRead[T, PT](ctx, req, resp, r.client, r.path, r.name, r.read)
+}
+
+func (r *genericResource[T, PT]) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	genericUpdate[T, PT](ctx, req, resp, r.client, r.path, r.name, r.read, r.write)
+}
+
+func (r *genericResource[T, PT]) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	genericDelete[T, PT](ctx, req, resp, r.client, r.path, r.name)
+}
+
+func (r *genericResource[T, PT]) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	genericImportState(ctx, req, resp, r.client, r.path, r.name, r.importFields)
+}
+
+// genericImportState imports an entity by its id, or, when importFields are
+// set, by matching the import ID against those fields of the entities listed
+// at listPath, allowing import by e.g. code or name.
+func genericImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse, client *m3terClient, listPath, name string, importFields []string) {
+	if len(importFields) > 0 && !isUUID(req.ID) {
+		var ids []string
+		var err error
+		// The API filters by code, which saves listing every entity
+		if slices.Contains(importFields, "code") {
+			query := url.Values{}
+			query.Set("codes", req.ID)
+			ids, err = findImportIDs(ctx, client, listPath, importFields, query, req.ID)
+		}
+		otherFields := slices.ContainsFunc(importFields, func(field string) bool { return field != "code" })
+		if err == nil && len(ids) == 0 && otherFields {
+			ids, err = findImportIDs(ctx, client, listPath, importFields, nil, req.ID)
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to list %ss", name), err.Error())
+			return
+		}
+		if len(ids) == 0 {
+			resp.Diagnostics.AddError(strings.ToUpper(name[:1])+name[1:]+" not found", fmt.Sprintf("The %s with %s %s does not exist.", name, strings.Join(importFields, " or "), req.ID))
+			return
+		}
+		// Names aren't unique, and importing an arbitrary one of the matches
+		// could manage the wrong entity
+		if len(ids) > 1 {
+			resp.Diagnostics.AddError(fmt.Sprintf("Multiple matching %ss found", name), fmt.Sprintf("%d %ss have %s %s: %s. Import one of them by its id instead.", len(ids), name, strings.Join(importFields, " or "), req.ID, strings.Join(ids, ", ")))
+			return
+		}
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), ids[0])...)
+		return
+	}
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// findImportIDs returns the ids of the entities listed at listPath with query
+// which have one of importFields set to value.
+func findImportIDs(ctx context.Context, client *m3terClient, listPath string, importFields []string, query url.Values, value string) ([]string, error) {
+	var ids []string
+	err := client.list(ctx, listPath, query, func(restData map[string]any) bool {
+		for _, field := range importFields {
+			if v, ok := restData[field].(string); ok && v == value {
+				id, _ := restData["id"].(string)
+				ids = append(ids, id)
+				break
+			}
+		}
+		return true
+	})
+	return ids, err
+}
@@ -6,15 +6,31 @@ package provider
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func isUUID(s string) bool {
+	return uuidRegexp.MatchString(s)
+}
+
 type mapper struct {
 	ctx         context.Context
 	diagnostics *diag.Diagnostics
@@ -46,18 +62,29 @@ type float64Valuer interface {
 	ValueFloat64() float64
 }
 
+type bigFloatValuer interface {
+	ValueBigFloat() *big.Float
+}
+
 type boolValuer interface {
 	ValueBool() bool
 }
 
 func (m *mapper) to(key string, target attrTyped) {
-	if v, ok := m.v[key]; ok {
+	markMapped(m.ctx, key)
+	// A null value is treated the same as a missing one
+	if v, ok := m.v[key]; ok && v != nil {
 		m.diagnostics.Append(tfsdk.ValueFrom(m.ctx, v, target.Type(m.ctx), target)...)
 	}
 }
 
 func (m *mapper) listTo(key string, target *types.List, elemType attr.Type, fn func(any) (attr.Value, diag.Diagnostics)) {
+	markMapped(m.ctx, key)
 	if v, ok := m.v[key]; ok {
+		// A null list is treated the same as an empty one
+		if v == nil {
+			v = []any{}
+		}
 		if v, ok := v.([]any); ok {
 			if target.IsNull() && len(v) == 0 {
 				return
@@ -75,7 +102,67 @@ func (m *mapper) listTo(key string, target *types.List, elemType attr.Type, fn f
 	}
 }
 
+// setTo maps the array at key to target, a set with the given element type,
+// the same way as listTo.
+func (m *mapper) setTo(key string, target *types.Set, elemType attr.Type, fn func(any) (attr.Value, diag.Diagnostics)) {
+	markMapped(m.ctx, key)
+	if v, ok := m.v[key]; ok {
+		// A null set is treated the same as an empty one
+		if v == nil {
+			v = []any{}
+		}
+		if v, ok := v.([]any); ok {
+			if target.IsNull() && len(v) == 0 {
+				return
+			}
+			var elements []attr.Value
+			for _, e := range v {
+				elem, diag := fn(e)
+				m.diagnostics.Append(diag...)
+				elements = append(elements, elem)
+			}
+			sv, diag := types.SetValue(elemType, elements)
+			m.diagnostics.Append(diag...)
+			*target = sv
+		}
+	}
+}
+
+// objectTo maps the object at key to target, an object with the given
+// attribute types. fields maps each attribute name to the API field it is
+// read from. A missing or empty object reads as null.
+func (m *mapper) objectTo(key string, target *types.Object, attrTypes map[string]attr.Type, fields map[string]string) {
+	markMapped(m.ctx, key)
+	v, ok := m.v[key].(map[string]any)
+	if !ok || len(v) == 0 {
+		*target = types.ObjectNull(attrTypes)
+		return
+	}
+
+	attrs := make(map[string]attr.Value)
+	for k, typ := range attrTypes {
+		fv := v[fields[k]]
+		if fv == nil {
+			nv, err := typ.ValueFromTerraform(m.ctx, tftypes.NewValue(typ.TerraformType(m.ctx), nil))
+			if err != nil {
+				m.diagnostics.AddError("Cannot map field "+fields[k], err.Error())
+			}
+			attrs[k] = nv
+			continue
+		}
+
+		// ValueFrom needs a target of the attribute's concrete value type
+		av := reflect.New(reflect.TypeOf(typ.ValueType(m.ctx)))
+		m.diagnostics.Append(tfsdk.ValueFrom(m.ctx, fv, typ, av.Interface())...)
+		attrs[k] = av.Elem().Interface().(attr.Value)
+	}
+	ov, diag := types.ObjectValue(attrTypes, attrs)
+	m.diagnostics.Append(diag...)
+	*target = ov
+}
+
 func (m *mapper) customFieldsTo(target *types.Dynamic) {
+	markMapped(m.ctx, "customFields")
 	if target.IsUnknown() || target.IsUnderlyingValueUnknown() {
 		mv, diag := types.MapValueFrom(m.ctx, types.DynamicType, m.v["customFields"])
 		m.diagnostics.Append(diag...)
@@ -106,6 +193,9 @@ func (m *mapper) customFieldsTo(target *types.Dynamic) {
 				case float64:
 					typ[k] = types.Float64Type
 					translated[k] = types.Float64Value(v)
+				case bool:
+					typ[k] = types.BoolType
+					translated[k] = types.BoolValue(v)
 				default:
 					m.diagnostics.AddError("Invalid custom field value", fmt.Sprintf("Custom field %s has an invalid value type: %T", k, v))
 				}
@@ -121,6 +211,53 @@ func (m *mapper) customFieldsTo(target *types.Dynamic) {
 	}
 }
 
+type mappedKeysKey struct{}
+
+// trackMappedKeys returns a context in which the API response keys consumed
+// by mappers are recorded, and the set they are recorded in.
+func trackMappedKeys(ctx context.Context) (context.Context, map[string]bool) {
+	mapped := make(map[string]bool)
+	return context.WithValue(ctx, mappedKeysKey{}, mapped), mapped
+}
+
+// markMapped records API response keys read without the mapper, so they are
+// not reported as unmapped.
+func markMapped(ctx context.Context, keys ...string) {
+	if mapped, ok := ctx.Value(mappedKeysKey{}).(map[string]bool); ok {
+		for _, key := range keys {
+			mapped[key] = true
+		}
+	}
+}
+
+// auditKeys are returned for every entity but are not exposed as attributes.
+var auditKeys = []string{"createdBy", "lastModifiedBy", "dtCreated", "dtLastModified"}
+
+// checkUnmappedKeys reports API response keys the provider doesn't map, which
+// usually means m3ter has added fields the provider should support. They are
+// logged at debug level, or reported as an error in strict mode.
+func checkUnmappedKeys(ctx context.Context, client *m3terClient, name string, restData map[string]any, mapped map[string]bool, diagnostics *diag.Diagnostics) {
+	var unmapped []string
+	for key := range restData {
+		if !mapped[key] && !slices.Contains(auditKeys, key) {
+			unmapped = append(unmapped, key)
+		}
+	}
+	if len(unmapped) == 0 {
+		return
+	}
+	slices.Sort(unmapped)
+
+	if client.strictMode {
+		diagnostics.AddError("Unmapped API fields", fmt.Sprintf("The API returned fields for the %s that the provider does not map: %s", name, strings.Join(unmapped, ", ")))
+		return
+	}
+	tflog.Debug(ctx, "m3ter API returned unmapped fields", map[string]any{
+		"entity": name,
+		"fields": unmapped,
+	})
+}
+
 func (m *mapper) from(source unknowable, target string) {
 	if source.IsUnknown() || source.IsNull() {
 		return
@@ -135,6 +272,9 @@ func (m *mapper) from(source unknowable, target string) {
 		m.v[target] = source.ValueInt64()
 	case float64Valuer:
 		m.v[target] = source.ValueFloat64()
+	case bigFloatValuer:
+		// The API stores numbers as doubles
+		m.v[target], _ = source.ValueBigFloat().Float64()
 	case boolValuer:
 		m.v[target] = source.ValueBool()
 	default:
@@ -156,6 +296,42 @@ func (m *mapper) listFrom(source types.List, target string, fn func(v attr.Value
 	m.v[target] = v
 }
 
+func (m *mapper) setFrom(source types.Set, target string, fn func(v attr.Value) (any, diag.Diagnostics)) {
+	if source.IsUnknown() {
+		return
+	}
+
+	v := make([]any, 0, len(source.Elements()))
+	for _, e := range source.Elements() {
+		elem, diag := fn(e)
+		m.diagnostics.Append(diag...)
+		v = append(v, elem)
+	}
+	m.v[target] = v
+}
+
+// objectFrom maps source to an object at target. fields maps each attribute
+// name to the API field it is written to. A null object is written as an
+// empty one, so its fields are cleared.
+func (m *mapper) objectFrom(source types.Object, target string, fields map[string]string) {
+	if source.IsUnknown() {
+		return
+	}
+
+	v := make(map[string]any)
+	if !source.IsNull() {
+		objM := &mapper{
+			ctx:         m.ctx,
+			diagnostics: m.diagnostics,
+			v:           v,
+		}
+		for k, av := range source.Attributes() {
+			objM.from(av, fields[k])
+		}
+	}
+	m.v[target] = v
+}
+
 func (m *mapper) customFieldsFrom(source types.Dynamic) {
 	if !source.IsUnknown() {
 		customFields := make(map[string]any)
@@ -187,10 +363,12 @@ func (m *mapper) customFieldsFrom(source types.Dynamic) {
 				case types.Number:
 					f, _ := v.ValueBigFloat().Float64()
 					return f
+				case types.Bool:
+					return v.ValueBool()
 				case types.Dynamic:
 					return convertMapValue(v.UnderlyingValue())
 				default:
-					m.diagnostics.AddError("Invalid custom field value", fmt.Sprintf("Custom field has an invalid value type: %T, must be a string or number", v))
+					m.diagnostics.AddError("Invalid custom field value", fmt.Sprintf("Custom field has an invalid value type: %T, must be a string, number or boolean", v))
 					return nil
 				}
 			}
@@ -205,6 +383,131 @@ func (m *mapper) customFieldsFrom(source types.Dynamic) {
 	}
 }
 
+// findIdByCode returns the id of the entity at the given list endpoint whose
+// code matches code. The API filters the list by code, and the code is still
+// checked in case an endpoint ignores the filter.
+func findIdByCode(ctx context.Context, client *m3terClient, path, code string) (string, error) {
+	query := url.Values{}
+	query.Set("codes", code)
+	var id string
+	err := client.list(ctx, path, query, func(restData map[string]any) bool {
+		if restCode, ok := restData["code"].(string); ok && restCode == code {
+			id, _ = restData["id"].(string)
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return "", err
+	}
+	if id == "" {
+		return "", fmt.Errorf("no entity found at %s with code %q", path, code)
+	}
+	return id, nil
+}
+
+// resolveProductCode sets the product of the API entity to the one with the
+// given code, for resources which accept a product_code instead of a
+// product_id.
+func resolveProductCode(ctx context.Context, client *m3terClient, productCode types.String, restData map[string]any, diagnostics *diag.Diagnostics) {
+	if productCode.IsUnknown() || productCode.IsNull() {
+		return
+	}
+
+	productId, err := findIdByCode(ctx, client, "/products", productCode.ValueString())
+	if err != nil {
+		diagnostics.AddAttributeError(path.Root("product_code"), "Unable to resolve product code", err.Error())
+		return
+	}
+	restData["productId"] = productId
+}
+
+// findDependents returns the ids of the entities at the given list endpoint
+// whose field references id.
+func findDependents(ctx context.Context, client *m3terClient, path, field, id string) ([]string, error) {
+	var ids []string
+	err := client.list(ctx, path, nil, func(restData map[string]any) bool {
+		if ref, ok := restData[field].(string); ok && ref == id {
+			if dependentId, ok := restData["id"].(string); ok {
+				ids = append(ids, dependentId)
+			}
+		}
+		return true
+	})
+	return ids, err
+}
+
+// checkRequiredCustomFields reports an error for each custom field that the
+// provider configuration requires but which is missing from the planned
+// custom_fields attribute.
+func checkRequiredCustomFields(ctx context.Context, client *m3terClient, plan tfsdk.Plan, diagnostics *diag.Diagnostics) {
+	// Nothing to check on destroy, or before the provider is configured
+	if plan.Raw.IsNull() || client == nil || len(client.requiredCustomFields) == 0 {
+		return
+	}
+
+	var customFields types.Dynamic
+	diagnostics.Append(plan.GetAttribute(ctx, path.Root("custom_fields"), &customFields)...)
+	if diagnostics.HasError() || customFields.IsUnknown() || customFields.IsUnderlyingValueUnknown() {
+		return
+	}
+
+	var elements map[string]attr.Value
+	switch v := customFields.UnderlyingValue().(type) {
+	case types.Map:
+		elements = v.Elements()
+	case types.Object:
+		elements = v.Attributes()
+	}
+
+	for _, key := range client.requiredCustomFields {
+		if _, ok := elements[key]; !ok {
+			diagnostics.AddAttributeError(
+				path.Root("custom_fields"),
+				"Missing required custom field",
+				fmt.Sprintf("The provider configuration requires the custom field %q to be set.", key),
+			)
+		}
+	}
+}
+
+// operationTimeout bounds how long a single resource operation, including any
+// request retries, may take when the resource's timeouts block doesn't set a
+// timeout for it.
+const operationTimeout = 20 * time.Minute
+
+// attributeGetter is the plan or state of a resource.
+type attributeGetter interface {
+	GetAttribute(ctx context.Context, path path.Path, target any) diag.Diagnostics
+}
+
+// withOperationTimeout returns a context bounded by the timeout the timeouts
+// block of the plan or state sets for the operation, one of create, read,
+// update or delete, or by operationTimeout when it sets none.
+func withOperationTimeout(ctx context.Context, data attributeGetter, operation string, diagnostics *diag.Diagnostics) (context.Context, context.CancelFunc) {
+	var value timeouts.Value
+	diagnostics.Append(data.GetAttribute(ctx, path.Root("timeouts"), &value)...)
+
+	timeout := operationTimeout
+	var diags diag.Diagnostics
+	switch operation {
+	case "create":
+		timeout, diags = value.Create(ctx, operationTimeout)
+	case "read":
+		timeout, diags = value.Read(ctx, operationTimeout)
+	case "update":
+		timeout, diags = value.Update(ctx, operationTimeout)
+	case "delete":
+		timeout, diags = value.Delete(ctx, operationTimeout)
+	}
+	diagnostics.Append(diags...)
+	return context.WithTimeout(ctx, timeout)
+}
+
+// maxConflictRetries is how many times an update failing with a version
+// conflict is retried with the entity read again.
+const maxConflictRetries = 3
+
 type idable[T any] interface {
 	*T
 
@@ -212,6 +515,11 @@ type idable[T any] interface {
 }
 
 func genericCreate[T any](ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse, client *m3terClient, path, name string, read func(context.Context, *T, map[string]any, *diag.Diagnostics), write func(context.Context, *T, map[string]any, *diag.Diagnostics)) {
+	ctx, cancel := withOperationTimeout(ctx, req.Plan, "create", &resp.Diagnostics)
+	defer cancel()
+	ctx, logCalls := countAPICalls(ctx, "create", name)
+	defer logCalls()
+
 	var data T
 
 	// Read Terraform plan data into the model
@@ -232,7 +540,9 @@ func genericCreate[T any](ctx context.Context, req resource.CreateRequest, resp
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create %s, got error: %s", name, err))
 	}
 
-	read(ctx, &data, updatedRestData, &resp.Diagnostics)
+	readCtx, mapped := trackMappedKeys(ctx)
+	read(readCtx, &data, updatedRestData, &resp.Diagnostics)
+	checkUnmappedKeys(ctx, client, name, updatedRestData, mapped, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -241,7 +551,12 @@ func genericCreate[T any](ctx context.Context, req resource.CreateRequest, resp
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func genericRead[T any, PT idable[T]](ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse, client *m3terClient, path, name string, read func(context.Context, PT, map[string]any, *diag.Diagnostics)) {
+func genericRead[T any, PT idable[T]](ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse, client *m3terClient, path, name string, read func(context.Context, *T, map[string]any, *diag.Diagnostics)) {
+	ctx, cancel := withOperationTimeout(ctx, req.State, "read", &resp.Diagnostics)
+	defer cancel()
+	ctx, logCalls := countAPICalls(ctx, "read", name)
+	defer logCalls()
+
 	var data T
 
 	// Read Terraform prior state data into the model
@@ -258,13 +573,20 @@ func genericRead[T any, PT idable[T]](ctx context.Context, req resource.ReadRequ
 		return
 	}
 
-	read(ctx, &data, restData, &resp.Diagnostics)
+	readCtx, mapped := trackMappedKeys(ctx)
+	read(readCtx, &data, restData, &resp.Diagnostics)
+	checkUnmappedKeys(ctx, client, name, restData, mapped, &resp.Diagnostics)
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func genericUpdate[T any, PT idable[T]](ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse, client *m3terClient, path, name string, read func(context.Context, PT, map[string]any, *diag.Diagnostics), write func(context.Context, PT, map[string]any, *diag.Diagnostics)) {
+func genericUpdate[T any, PT idable[T]](ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse, client *m3terClient, path, name string, read func(context.Context, *T, map[string]any, *diag.Diagnostics), write func(context.Context, *T, map[string]any, *diag.Diagnostics)) {
+	ctx, cancel := withOperationTimeout(ctx, req.Plan, "update", &resp.Diagnostics)
+	defer cancel()
+	ctx, logCalls := countAPICalls(ctx, "update", name)
+	defer logCalls()
+
 	var data T
 
 	// Read Terraform plan data into the model
@@ -273,25 +595,52 @@ func genericUpdate[T any, PT idable[T]](ctx context.Context, req resource.Update
 		return
 	}
 
-	var restData map[string]any
-	err := client.execute(ctx, "GET", path+"/"+url.PathEscape(PT(&data).GetId().ValueString()), nil, nil, &restData)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read %s, got error: %s", name, err))
-		return
-	}
+	entityPath := path + "/" + url.PathEscape(PT(&data).GetId().ValueString())
+	var newRestData map[string]any
+	for attempt := 0; ; attempt++ {
+		var restData map[string]any
+		err := client.execute(ctx, "GET", entityPath, nil, nil, &restData)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read %s, got error: %s", name, err))
+			return
+		}
 
-	write(ctx, &data, restData, &resp.Diagnostics)
-	if resp.Diagnostics.HasError() {
-		return
-	}
+		// The planned version is sent so that the API rejects an update of an
+		// entity changed since it was read, and only a retry after such a
+		// conflict sends the version just read
+		version, hasVersion := restData["version"]
+		write(ctx, &data, restData, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if attempt > 0 && hasVersion {
+			restData["version"] = version
+		}
 
-	var newRestData map[string]any
-	err = client.execute(ctx, "PUT", path+"/"+url.PathEscape(PT(&data).GetId().ValueString()), nil, restData, &newRestData)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update %s, got error: %s", name, err))
+		err = client.execute(ctx, "PUT", entityPath, nil, restData, &newRestData)
+		if isConflict(err) && attempt < maxConflictRetries {
+			// The entity changed between reading and updating it, such as
+			// in a concurrent apply, so read the new version and try again
+			tflog.Debug(ctx, "Retrying update after version conflict", map[string]any{
+				"entity":  name,
+				"attempt": attempt + 1,
+			})
+			continue
+		}
+		if isConflict(err) {
+			addVersionConflictError(&resp.Diagnostics, name, err)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update %s, got error: %s", name, err))
+			return
+		}
+		break
 	}
 
-	read(ctx, &data, newRestData, &resp.Diagnostics)
+	readCtx, mapped := trackMappedKeys(ctx)
+	read(readCtx, &data, newRestData, &resp.Diagnostics)
+	checkUnmappedKeys(ctx, client, name, newRestData, mapped, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -301,6 +650,11 @@ func genericUpdate[T any, PT idable[T]](ctx context.Context, req resource.Update
 }
 
 func genericDelete[T any, PT idable[T]](ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse, client *m3terClient, path, name string) {
+	ctx, cancel := withOperationTimeout(ctx, req.State, "delete", &resp.Diagnostics)
+	defer cancel()
+	ctx, logCalls := countAPICalls(ctx, "delete", name)
+	defer logCalls()
+
 	var data T
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
@@ -308,7 +662,34 @@ func genericDelete[T any, PT idable[T]](ctx context.Context, req resource.Delete
 	}
 
 	err := client.execute(ctx, "DELETE", path+"/"+url.PathEscape(PT(&data).GetId().ValueString()), nil, nil, nil)
+	// The entity was already deleted outside Terraform
+	if sc, ok := err.(*statusCodeError); ok && sc.StatusCode == 404 {
+		return
+	}
+	// A delete isn't versioned, so a conflict means the entity is in use
+	if isConflict(err) {
+		addInUseError(&resp.Diagnostics, name, err)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete %s, got error: %s", name, err))
 	}
 }
+
+// addVersionConflictError reports a 409 from the API on update, which it
+// returns when the version sent doesn't match the entity's current version.
+func addVersionConflictError(diagnostics *diag.Diagnostics, name string, err error) {
+	diagnostics.AddError(
+		"Version conflict",
+		fmt.Sprintf("The %s was modified outside Terraform (version conflict); refresh and retry. Got error: %s", name, err),
+	)
+}
+
+// addInUseError reports a 409 from the API on delete, which it returns when
+// the entity is still referenced by other entities.
+func addInUseError(diagnostics *diag.Diagnostics, name string, err error) {
+	diagnostics.AddError(
+		strings.ToUpper(name[:1])+name[1:]+" in use",
+		fmt.Sprintf("The %s is still in use and must be detached from its dependents before it can be deleted. Got error: %s", name, err),
+	)
+}
@@ -0,0 +1,333 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestGenericDeleteTimeout(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, map[string]any{"max_retries": 100})
+
+	config := map[string]any{
+		"name":          "Product",
+		"code":          "product",
+		"custom_fields": map[string]any{},
+		"timeouts":      map[string]any{"delete": "200ms"},
+	}
+	state := p.create("m3ter_product", config)
+
+	// The API never recovers, so the delete is retried until its timeout
+	api.handle(func(w http.ResponseWriter, req *fakeRequest) bool {
+		if req.Method != http.MethodDelete {
+			return false
+		}
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{"message": "unavailable"})
+		return true
+	})
+
+	start := time.Now()
+	diags := p.destroy("m3ter_product", state)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("delete took %s, want it bounded by its 200ms timeout", elapsed)
+	}
+	if summary := diagnosticsSummary(diags); !hasErrors(diags) || !strings.Contains(summary, "Unable to delete product") {
+		t.Errorf("got diagnostics %q, want the delete to fail", summary)
+	}
+	if attempts := len(api.requestsTo(http.MethodDelete, "/products/")); attempts < 2 {
+		t.Errorf("sent %d delete requests, want them retried until the timeout", attempts)
+	}
+}
+
+func TestGenericDeleteInUse(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	state := p.create("m3ter_product", map[string]any{
+		"name":          "Product",
+		"code":          "product",
+		"custom_fields": map[string]any{},
+	})
+	api.handle(func(w http.ResponseWriter, req *fakeRequest) bool {
+		if req.Method != http.MethodDelete {
+			return false
+		}
+		writeJSON(w, http.StatusConflict, map[string]any{"message": "product is referenced by a plan"})
+		return true
+	})
+
+	summary := diagnosticsSummary(p.destroy("m3ter_product", state))
+	if !strings.Contains(summary, "Product in use") || !strings.Contains(summary, "product is referenced by a plan") {
+		t.Errorf("got diagnostics %q, want the product reported in use with the API message", summary)
+	}
+	if strings.Contains(summary, "version conflict") {
+		t.Errorf("got diagnostics %q, want no version conflict reported on delete", summary)
+	}
+}
+
+func TestGenericUpdateCoupledVersions(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	// A meter, its aggregation and a pricing of it are applied together
+	meterConfig := testMeterConfig()
+	meter := p.create("m3ter_meter", meterConfig)
+	aggregationConfig := map[string]any{
+		"name":              "API calls",
+		"code":              "api_calls",
+		"meter_id":          attrValue(t, meter, "id"),
+		"target_field":      "calls",
+		"aggregation":       "SUM",
+		"rounding":          "NONE",
+		"quantity_per_unit": 1,
+		"unit":              "{call}",
+		"default_value":     0,
+		"custom_fields":     map[string]any{},
+	}
+	aggregation := p.create("m3ter_aggregation", aggregationConfig)
+	pricingConfig := testPricingConfig()
+	pricingConfig["aggregation_id"] = attrValue(t, aggregation, "id")
+	pricing := p.create("m3ter_pricing", pricingConfig)
+
+	// Writing the aggregation bumped the version of the meter, leaving the
+	// version in the meter's state stale
+	meterId := attrValue(t, meter, "id").(string)
+	if v := attrValue(t, meter, "version"); v == api.get("meters", meterId)["version"] {
+		t.Fatalf("meter version = %v, want it stale after the aggregation was written", v)
+	}
+
+	// Each apply updates all three from the state the previous one stored,
+	// where the versions of the meter and the aggregation are stale
+	api.clearRequests()
+	for i, name := range []string{"API calls v2", "API calls v3"} {
+		meterConfig["name"] = name
+		meter = p.update("m3ter_meter", meter, meterConfig)
+		aggregationConfig["name"] = name
+		aggregation = p.update("m3ter_aggregation", aggregation, aggregationConfig)
+		pricingConfig["description"] = name
+		pricing = p.update("m3ter_pricing", pricing, pricingConfig)
+
+		// Only the pricing's version is current, as nothing written after it
+		// depends on it
+		stored := api.get("pricings", attrValue(t, pricing, "id").(string))
+		if v := attrValue(t, pricing, "version"); v != stored["version"] {
+			t.Errorf("apply %d: pricing version = %v, want the stored version %v", i+1, v, stored["version"])
+		}
+		if v := attrValue(t, meter, "version"); v == api.get("meters", meterId)["version"] {
+			t.Errorf("apply %d: meter version = %v, want it stale after the aggregation update", i+1, v)
+		}
+	}
+
+	// No update was sent with a stale version, which would have failed with
+	// a conflict and been retried
+	if puts := api.requestsTo(http.MethodPut, "/"); len(puts) != 6 {
+		t.Errorf("sent %d updates, want 6 without any conflict", len(puts))
+	}
+	p.assertNoChanges("m3ter_meter", meter, meterConfig)
+	p.assertNoChanges("m3ter_aggregation", aggregation, aggregationConfig)
+	p.assertNoChanges("m3ter_pricing", pricing, pricingConfig)
+}
+
+func TestGenericUpdateConflictRetry(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	config := map[string]any{
+		"name":          "Product",
+		"code":          "product",
+		"custom_fields": map[string]any{},
+	}
+	state := p.create("m3ter_product", config)
+	id := attrValue(t, state, "id").(string)
+
+	// The product is changed concurrently just before the first update
+	conflicted := false
+	api.handle(func(w http.ResponseWriter, req *fakeRequest) bool {
+		if req.Method != http.MethodPut || conflicted {
+			return false
+		}
+		conflicted = true
+		product := maps.Clone(api.get("products", id))
+		product["version"] = product["version"].(float64) + 1
+		api.put("products", product)
+		writeJSON(w, http.StatusConflict, map[string]any{"message": "version mismatch"})
+		return true
+	})
+
+	config["name"] = "Renamed product"
+	api.clearRequests()
+	state = p.update("m3ter_product", state, config)
+
+	puts := api.requestsTo(http.MethodPut, "/products/"+id)
+	if len(puts) != 2 {
+		t.Fatalf("sent %d updates, want the conflict retried once", len(puts))
+	}
+	if v := puts[0].Body["version"]; v != float64(1) {
+		t.Errorf("first update sent version %v, want the planned 1", v)
+	}
+	if v := puts[1].Body["version"]; v != float64(2) {
+		t.Errorf("retry sent version %v, want the refreshed 2", v)
+	}
+	if v := attrValue(t, state, "version"); v != float64(3) {
+		t.Errorf("version = %v, want 3 from the update response", v)
+	}
+	if v := api.get("products", id)["name"]; v != "Renamed product" {
+		t.Errorf("stored name = %v, want the update applied", v)
+	}
+
+	// A conflict persisting after the retries is reported
+	api.handle(func(w http.ResponseWriter, req *fakeRequest) bool {
+		if req.Method != http.MethodPut {
+			return false
+		}
+		writeJSON(w, http.StatusConflict, map[string]any{"message": "version mismatch"})
+		return true
+	})
+	config["name"] = "Product"
+	api.clearRequests()
+	planned, diags := p.plan("m3ter_product", state, config)
+	p.checkDiagnostics("plan", diags)
+	_, diags = p.apply("m3ter_product", state, planned, config)
+	if summary := diagnosticsSummary(diags); !strings.Contains(summary, "Version conflict") {
+		t.Errorf("got diagnostics %q, want a version conflict", summary)
+	}
+	if puts := api.requestsTo(http.MethodPut, "/products/"+id); len(puts) != maxConflictRetries+1 {
+		t.Errorf("sent %d updates, want %d", len(puts), maxConflictRetries+1)
+	}
+}
+
+func TestImportByCodeAcrossPages(t *testing.T) {
+	for _, tt := range []struct {
+		typeName, collection string
+	}{
+		{"m3ter_meter", "meters"},
+		{"m3ter_counter", "counters"},
+		{"m3ter_aggregation", "aggregations"},
+		{"m3ter_plan_template", "plantemplates"},
+		{"m3ter_product", "products"},
+	} {
+		t.Run(tt.typeName, func(t *testing.T) {
+			api := newFakeAPI(t)
+			api.pageSize = 1
+			// The codes filter is recorded then ignored, so every entity is
+			// listed one per page and the code only matches on the second page
+			var codes []string
+			api.handle(func(w http.ResponseWriter, req *fakeRequest) bool {
+				if req.Method == http.MethodGet && req.Path == "/"+tt.collection {
+					codes = append(codes, req.Query.Get("codes"))
+					req.Query.Del("codes")
+				}
+				return false
+			})
+			p := newTestProvider(t, api, nil)
+
+			api.put(tt.collection, map[string]any{"name": "First", "code": "first"})
+			id := api.put(tt.collection, map[string]any{"name": "Second", "code": "second"})
+
+			api.clearRequests()
+			state := p.importState(tt.typeName, "second")
+			if v := attrValue(t, state, "id"); v != id {
+				t.Errorf("id = %v, want %s", v, id)
+			}
+			var pages []string
+			for _, req := range api.requestsTo(http.MethodGet, "/"+tt.collection) {
+				if req.Path == "/"+tt.collection {
+					pages = append(pages, req.Query.Get("nextToken"))
+				}
+			}
+			if !slices.Equal(pages, []string{"", "1"}) {
+				t.Errorf("listed pages %q, want the first two", pages)
+			}
+			if !slices.Equal(codes, []string{"second", "second"}) {
+				t.Errorf("listed with codes %q, want every page filtered by the code", codes)
+			}
+
+			// Failing to list reports an error rather than importing the code
+			// as an id
+			api.handle(func(w http.ResponseWriter, req *fakeRequest) bool {
+				writeJSON(w, http.StatusBadRequest, map[string]any{"message": "bad request"})
+				return true
+			})
+			_, diags := p.tryImportState(tt.typeName, "second")
+			if !hasErrors(diags) {
+				t.Errorf("a failed list was not reported")
+			}
+		})
+	}
+}
+
+func TestMapperObject(t *testing.T) {
+	ctx := context.Background()
+	attrTypes := map[string]attr.Type{
+		"post_code": types.StringType,
+		"floor":     types.Int64Type,
+		"primary":   types.BoolType,
+	}
+	fields := map[string]string{
+		"post_code": "postCode",
+		"floor":     "floor",
+		"primary":   "primary",
+	}
+
+	var diags diag.Diagnostics
+	m := &mapper{ctx: ctx, diagnostics: &diags, v: map[string]any{
+		"address": map[string]any{"postCode": "SW1A 1AA", "floor": float64(3), "primary": nil},
+	}}
+	var address types.Object
+	m.objectTo("address", &address, attrTypes, fields)
+	want, d := types.ObjectValue(attrTypes, map[string]attr.Value{
+		"post_code": types.StringValue("SW1A 1AA"),
+		"floor":     types.Int64Value(3),
+		"primary":   types.BoolNull(),
+	})
+	diags.Append(d...)
+	if !address.Equal(want) {
+		t.Errorf("objectTo = %s, want %s", address, want)
+	}
+
+	// Mapping the object back sends the set attributes under their API names
+	out := &mapper{ctx: ctx, diagnostics: &diags, v: map[string]any{}}
+	out.objectFrom(address, "address", fields)
+	if got, want := out.v["address"], map[string]any{"postCode": "SW1A 1AA", "floor": int64(3)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("objectFrom = %v, want %v", got, want)
+	}
+
+	// A missing or empty object reads as null
+	for _, v := range []map[string]any{{}, {"address": map[string]any{}}, {"address": nil}} {
+		m.v = v
+		address = want
+		m.objectTo("address", &address, attrTypes, fields)
+		if !address.IsNull() {
+			t.Errorf("objectTo(%v) = %s, want null", v, address)
+		}
+	}
+
+	// A null object is sent empty, and an unknown one not at all
+	out.v = map[string]any{}
+	out.objectFrom(types.ObjectNull(attrTypes), "address", fields)
+	if got := out.v["address"]; !reflect.DeepEqual(got, map[string]any{}) {
+		t.Errorf("objectFrom(null) = %v, want an empty object", got)
+	}
+	out.v = map[string]any{}
+	out.objectFrom(types.ObjectUnknown(attrTypes), "address", fields)
+	if _, ok := out.v["address"]; ok {
+		t.Errorf("objectFrom(unknown) sent %v, want nothing", out.v["address"])
+	}
+
+	if diags.HasError() {
+		t.Errorf("got diagnostics %v", diags)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"regexp"
 
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -35,15 +37,31 @@ type IntegrationConfigurationResource struct {
 
 // IntegrationConfigurationResourceModel describes the resource data model.
 type IntegrationConfigurationResourceModel struct {
-	EntityType               types.String `tfsdk:"entity_type"`
-	EntityId                 types.String `tfsdk:"entity_id"`
-	Destination              types.String `tfsdk:"destination"`
-	DestinationId            types.String `tfsdk:"destination_id"`
-	ConfigData               types.String `tfsdk:"config_data"`
-	Name                     types.String `tfsdk:"name"`
-	IntegrationCredentialsId types.String `tfsdk:"integration_credentials_id"`
-	Id                       types.String `tfsdk:"id"`
-	Version                  types.Int64  `tfsdk:"version"`
+	EntityType               types.String         `tfsdk:"entity_type"`
+	EntityId                 types.String         `tfsdk:"entity_id"`
+	EntityCode               types.String         `tfsdk:"entity_code"`
+	Destination              types.String         `tfsdk:"destination"`
+	DestinationId            types.String         `tfsdk:"destination_id"`
+	ConfigData               jsontypes.Normalized `tfsdk:"config_data"`
+	Name                     types.String         `tfsdk:"name"`
+	IntegrationCredentialsId types.String         `tfsdk:"integration_credentials_id"`
+	Id                       types.String         `tfsdk:"id"`
+	Version                  types.Int64          `tfsdk:"version"`
+	Timeouts                 timeouts.Value       `tfsdk:"timeouts"`
+}
+
+// integrationEntityPaths maps the entity types that can be resolved by code to
+// the list endpoint used to look them up.
+var integrationEntityPaths = map[string]string{
+	"Account":             "/accounts",
+	"Aggregation":         "/aggregations",
+	"CompoundAggregation": "/compoundaggregations",
+	"Counter":             "/counters",
+	"Meter":               "/meters",
+	"Notification":        "/notifications/configurations",
+	"Plan":                "/plans",
+	"PlanTemplate":        "/plantemplates",
+	"Product":             "/products",
 }
 
 func (r *IntegrationConfigurationResourceModel) GetId() types.String {
@@ -70,6 +88,15 @@ func (r *IntegrationConfigurationResource) Schema(ctx context.Context, req resou
 			"entity_id": schema.StringAttribute{
 				MarkdownDescription: "The unique identifier (UUID) of the entity. This field is used to specify which entity's integration configuration you're updating.",
 				Optional:            true,
+				Computed:            true,
+			},
+			"entity_code": schema.StringAttribute{
+				MarkdownDescription: "The code of the entity, resolved to `entity_id` at apply time. Supported for the `Account`, `Aggregation`, `CompoundAggregation`, `Counter`, `Meter`, `Notification`, `Plan`, `PlanTemplate` and `Product` entity types. Conflicts with `entity_id`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("entity_id")),
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
 			"destination": schema.StringAttribute{
 				MarkdownDescription: "Denotes the integration destination. This field identifies the target platform or service for the integration.",
@@ -86,6 +113,8 @@ func (r *IntegrationConfigurationResource) Schema(ctx context.Context, req resou
 			"config_data": schema.StringAttribute{
 				MarkdownDescription: "A flexible object to include any additional configuration data specific to the integration.",
 				Required:            true,
+				// Documents differing only in formatting or key order are equal
+				CustomType: jsontypes.NormalizedType{},
 			},
 			"integration_credentials_id": schema.StringAttribute{
 				MarkdownDescription: "The unique identifier (UUID) of the integration credentials. This field is used to specify the credentials used for the integration.",
@@ -111,6 +140,9 @@ func (r *IntegrationConfigurationResource) Schema(ctx context.Context, req resou
 				MarkdownDescription: "Integration Configuration version",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -165,15 +197,24 @@ func (r *IntegrationConfigurationResource) read(ctx context.Context, data *Integ
 	m.to("version", &data.Version)
 	m.to("name", &data.Name)
 	m.to("entityType", &data.EntityType)
-	m.to("entityId", &data.EntityId)
+	if v, ok := restData["entityId"]; ok && v != nil {
+		m.to("entityId", &data.EntityId)
+	} else {
+		data.EntityId = types.StringNull()
+	}
 	m.to("destination", &data.Destination)
-	m.to("destinationId", &data.DestinationId)
+	if v, ok := restData["destinationId"]; ok && v != nil {
+		m.to("destinationId", &data.DestinationId)
+	} else {
+		data.DestinationId = types.StringNull()
+	}
 	if _, ok := restData["integrationCredentialsId"]; !ok {
 		restData["integrationCredentialsId"] = ""
 	}
 	m.to("integrationCredentialsId", &data.IntegrationCredentialsId)
+	markMapped(ctx, "configData")
 	configData, _ := json.Marshal(restData["configData"])
-	data.ConfigData = types.StringValue(string(configData))
+	data.ConfigData = jsontypes.NewNormalizedValue(string(configData))
 }
 
 func (r *IntegrationConfigurationResource) write(ctx context.Context, data *IntegrationConfigurationResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
@@ -187,8 +228,25 @@ func (r *IntegrationConfigurationResource) write(ctx context.Context, data *Inte
 	m.from(data.Name, "name")
 	m.from(data.EntityType, "entityType")
 	m.from(data.EntityId, "entityId")
+	if !data.EntityCode.IsUnknown() && !data.EntityCode.IsNull() {
+		entityPath, ok := integrationEntityPaths[data.EntityType.ValueString()]
+		if !ok {
+			diagnostics.AddAttributeError(path.Root("entity_code"), "Unsupported entity type", fmt.Sprintf("Entities of type %s cannot be looked up by code, use entity_id instead.", data.EntityType.ValueString()))
+			return
+		}
+
+		entityId, err := findIdByCode(ctx, r.client, entityPath, data.EntityCode.ValueString())
+		if err != nil {
+			diagnostics.AddAttributeError(path.Root("entity_code"), "Unable to resolve entity code", err.Error())
+			return
+		}
+		restData["entityId"] = entityId
+	}
 	m.from(data.Destination, "destination")
 	m.from(data.DestinationId, "destinationId")
+	if data.DestinationId.IsNull() {
+		delete(restData, "destinationId")
+	}
 	if data.IntegrationCredentialsId.ValueString() != "" {
 		m.from(data.IntegrationCredentialsId, "integrationCredentialsId")
 	}
@@ -0,0 +1,127 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+)
+
+func TestIntegrationConfigurationResourceClearDestinationId(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	config := map[string]any{
+		"entity_type":    "Bill",
+		"entity_id":      "00000000-0000-4000-8000-000000000002",
+		"destination":    "Webhook",
+		"destination_id": "00000000-0000-4000-8000-000000000003",
+		"config_data":    `{"enabled": true}`,
+		"name":           "Bills",
+	}
+	state := p.create("m3ter_integration_configuration", config)
+	id := attrValue(t, state, "id").(string)
+
+	delete(config, "destination_id")
+	state = p.update("m3ter_integration_configuration", state, config)
+	if _, ok := api.get("integrationconfigs", id)["destinationId"]; ok {
+		t.Errorf("destinationId was sent after being cleared")
+	}
+
+	state = p.read("m3ter_integration_configuration", state)
+	if v := attrValue(t, state, "destination_id"); v != nil {
+		t.Errorf("destination_id = %v, want null", v)
+	}
+	p.assertNoChanges("m3ter_integration_configuration", state, config)
+}
+
+func TestIntegrationConfigurationResourceConfigData(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	// The API returns the document compacted with its keys sorted, which
+	// doesn't change its content
+	config := map[string]any{
+		"entity_type": "Bill",
+		"entity_id":   "00000000-0000-4000-8000-000000000002",
+		"destination": "Webhook",
+		"name":        "Bills",
+		"config_data": `{
+			"mapping": {"invoice": {"number": "billNumber", "date": "billDate"}},
+			"filters": [{"field": "currency", "values": ["USD", "EUR"]}, {"field": "total", "min": 0}],
+			"enabled": true
+		}`,
+	}
+	state := p.create("m3ter_integration_configuration", config)
+	if v := attrValue(t, state, "config_data"); v != config["config_data"] {
+		t.Errorf("config_data = %v, want the configured document kept", v)
+	}
+	state = p.read("m3ter_integration_configuration", state)
+	p.assertNoChanges("m3ter_integration_configuration", state, config)
+
+	// A reformatted document with reordered keys is planned as configured,
+	// and kept after being applied
+	config["config_data"] = `{"enabled": true, "filters": [{"values": ["USD", "EUR"], "field": "currency"}, {"min": 0, "field": "total"}], "mapping": {"invoice": {"date": "billDate", "number": "billNumber"}}}`
+	state = p.update("m3ter_integration_configuration", state, config)
+	state = p.read("m3ter_integration_configuration", state)
+	p.assertNoChanges("m3ter_integration_configuration", state, config)
+
+	// Reordering an array is
+	config["config_data"] = `{"enabled": true, "filters": [{"field": "currency", "values": ["EUR", "USD"]}, {"field": "total", "min": 0}], "mapping": {"invoice": {"date": "billDate", "number": "billNumber"}}}`
+	state = p.update("m3ter_integration_configuration", state, config)
+	id := attrValue(t, state, "id").(string)
+	filters := api.get("integrationconfigs", id)["configData"].(map[string]any)["filters"].([]any)
+	if values := filters[0].(map[string]any)["values"].([]any); values[0] != "EUR" {
+		t.Errorf("sent filter values %v, want EUR first", values)
+	}
+
+	config["config_data"] = `{"enabled": true`
+	_, diags := p.plan("m3ter_integration_configuration", state, config)
+	if !hasErrors(diags) {
+		t.Errorf("invalid JSON was accepted")
+	}
+}
+
+func TestIntegrationConfigurationResourceEntity(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+	api.put("meters", map[string]any{"code": "other"})
+	meterId := api.put("meters", map[string]any{"code": "storage"})
+
+	// A configuration for every entity of a type has no entity
+	config := map[string]any{
+		"entity_type": "Bill",
+		"destination": "Webhook",
+		"config_data": `{"enabled": true}`,
+		"name":        "Bills",
+	}
+	state := p.create("m3ter_integration_configuration", config)
+	if v := attrValue(t, state, "entity_id"); v != nil {
+		t.Errorf("entity_id = %v, want null", v)
+	}
+	p.assertNoChanges("m3ter_integration_configuration", state, config)
+
+	// An entity code is looked up with the API's filter
+	config = map[string]any{
+		"entity_type": "Meter",
+		"entity_code": "storage",
+		"destination": "Webhook",
+		"config_data": `{"enabled": true}`,
+		"name":        "Storage",
+	}
+	api.clearRequests()
+	state = p.create("m3ter_integration_configuration", config)
+	if v := attrValue(t, state, "entity_id"); v != meterId {
+		t.Errorf("entity_id = %v, want %s", v, meterId)
+	}
+	lists := api.requestsTo("GET", "/meters")
+	if len(lists) != 1 || lists[0].Query.Get("codes") != "storage" {
+		t.Errorf("the meter was not looked up filtered by code")
+	}
+
+	config["entity_id"] = meterId
+	_, diags := p.plan("m3ter_integration_configuration", state, config)
+	if !hasErrors(diags) {
+		t.Errorf("entity_code was accepted with entity_id")
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/url"
+	"sync"
+)
+
+// defaultListCacheSize is how many lists are cached by default.
+const defaultListCacheSize = 100
+
+// listCache holds the entities of list endpoints already fetched by the
+// provider instance, so many data sources looking up entities at the same
+// endpoint only paginate through it once. Any write clears it, as the entities
+// listed may have changed.
+type listCache struct {
+	// maxEntries bounds how many lists are cached, the oldest being evicted
+	// first
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*listCacheEntry
+	order   []string
+}
+
+type listCacheEntry struct {
+	// done is closed once the list has been fetched
+	done  chan struct{}
+	items []map[string]any
+	err   error
+}
+
+func newListCache(maxEntries int) *listCache {
+	return &listCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*listCacheEntry),
+	}
+}
+
+// get returns the cached entities listed at key, calling fetch to list them
+// if they aren't cached yet. Concurrent calls for the same key share a single
+// fetch, while failed fetches are not cached.
+func (c *listCache) get(ctx context.Context, key string, fetch func() ([]map[string]any, error)) ([]map[string]any, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &listCacheEntry{done: make(chan struct{})}
+		c.entries[key] = entry
+		c.order = append(c.order, key)
+		for len(c.order) > c.maxEntries {
+			delete(c.entries, c.order[0])
+			c.order = c.order[1:]
+		}
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		entry.items, entry.err = fetch()
+		close(entry.done)
+		if entry.err != nil {
+			c.remove(key, entry)
+		}
+		return entry.items, entry.err
+	}
+
+	select {
+	case <-entry.done:
+		return entry.items, entry.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// remove evicts the entry for key, unless it has already been replaced.
+func (c *listCache) remove(key string, entry *listCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries[key] != entry {
+		return
+	}
+	delete(c.entries, key)
+	for i, k := range c.order {
+		if k == key {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// clear evicts every entry.
+func (c *listCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*listCacheEntry)
+	c.order = nil
+}
+
+// cachedList calls fn with each entity listed at path like list, but fetches
+// the entities from the list cache when it is enabled.
+func (c *m3terClient) cachedList(ctx context.Context, path string, query url.Values, fn func(map[string]any) bool) error {
+	if c.listCache == nil {
+		return c.list(ctx, path, query, fn)
+	}
+
+	items, err := c.listCache.get(ctx, path+"?"+query.Encode(), func() ([]map[string]any, error) {
+		var items []map[string]any
+		err := c.list(ctx, path, query, func(restData map[string]any) bool {
+			items = append(items, restData)
+			return true
+		})
+		return items, err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if !fn(item) {
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &MeterDataSource{}
+
+func NewMeterDataSource() datasource.DataSource {
+	r := &MeterDataSource{}
+	r.genericDataSource = genericDataSource[MeterDataSourceModel, *MeterDataSourceModel]{
+		typeName: "meter",
+		path:     "/meters",
+		name:     "meter",
+		filters: func(data *MeterDataSourceModel) map[string]types.String {
+			return map[string]types.String{
+				"name": data.Name,
+				"code": data.Code,
+			}
+		},
+		extraQuery: func(data *MeterDataSourceModel) types.Map {
+			return data.ExtraQuery
+		},
+		read: r.read,
+	}
+	return r
+}
+
+// MeterDataSource defines the data source implementation.
+type MeterDataSource struct {
+	genericDataSource[MeterDataSourceModel, *MeterDataSourceModel]
+}
+
+type MeterDataSourceModel struct {
+	Name          types.String  `tfsdk:"name"`
+	Code          types.String  `tfsdk:"code"`
+	ProductId     types.String  `tfsdk:"product_id"`
+	GroupId       types.String  `tfsdk:"group_id"`
+	DataFields    types.List    `tfsdk:"data_fields"`
+	DerivedFields types.List    `tfsdk:"derived_fields"`
+	CustomFields  types.Dynamic `tfsdk:"custom_fields"`
+	Id            types.String  `tfsdk:"id"`
+	Version       types.Int64   `tfsdk:"version"`
+	ExtraQuery    types.Map     `tfsdk:"extra_query"`
+}
+
+func (r *MeterDataSourceModel) GetId() types.String {
+	return r.Id
+}
+
+var meterDataSourceFieldAttributes = map[string]schema.Attribute{
+	"category": schema.StringAttribute{
+		MarkdownDescription: "The field type, which defines the type of data collected in the field.",
+		Computed:            true,
+	},
+	"code": schema.StringAttribute{
+		MarkdownDescription: "Short code to identify the field",
+		Computed:            true,
+	},
+	"name": schema.StringAttribute{
+		MarkdownDescription: "Descriptive name for the field",
+		Computed:            true,
+	},
+	"unit": schema.StringAttribute{
+		MarkdownDescription: "The units to measure the data with.",
+		Computed:            true,
+	},
+}
+
+// meterDataSourceDerivedFieldAttributes are the attributes of derived fields,
+// which are data fields with a calculation.
+var meterDataSourceDerivedFieldAttributes = func() map[string]schema.Attribute {
+	attributes := map[string]schema.Attribute{
+		"calculation": schema.StringAttribute{
+			MarkdownDescription: "The calculation used to transform the value of submitted data fields.",
+			Computed:            true,
+		},
+	}
+	for k, v := range meterDataSourceFieldAttributes {
+		attributes[k] = v
+	}
+	return attributes
+}()
+
+func (r *MeterDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Meter data source",
+
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Descriptive name for the Meter.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"code": schema.StringAttribute{
+				MarkdownDescription: "Code of the Meter. A unique short code to identify the Meter.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"product_id": schema.StringAttribute{
+				MarkdownDescription: "UUID of the Product the Meter belongs to, if any.",
+				Computed:            true,
+			},
+			"group_id": schema.StringAttribute{
+				MarkdownDescription: "UUID of the group the Meter belongs to, if any.",
+				Computed:            true,
+			},
+			"data_fields": schema.ListNestedAttribute{
+				MarkdownDescription: "The fields of raw usage data collected by the Meter.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: meterDataSourceFieldAttributes,
+				},
+			},
+			"derived_fields": schema.ListNestedAttribute{
+				MarkdownDescription: "The fields calculated by the Meter from the data fields.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: meterDataSourceDerivedFieldAttributes,
+				},
+			},
+			"custom_fields": schema.DynamicAttribute{
+				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be a string, a number or a boolean.",
+				Computed:            true,
+			},
+			"id": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The UUID of the entity.",
+			},
+			"version": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The version number.",
+			},
+			"extra_query": extraQueryAttribute,
+		},
+	}
+}
+
+func (r *MeterDataSource) read(ctx context.Context, data *MeterDataSourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	// The meter is mapped the same way as by the resource
+	meter := MeterResourceModel{
+		CustomFields:  data.CustomFields,
+		DataFields:    types.ListNull(dataFieldsType.Type()),
+		DerivedFields: types.ListNull(derivedFieldsType.Type()),
+	}
+	(&MeterResource{}).read(ctx, &meter, restData, diagnostics)
+
+	data.Id = meter.Id
+	data.Version = meter.Version
+	data.Name = meter.Name
+	data.Code = meter.Code
+	data.ProductId = meter.ProductId
+	data.GroupId = meter.GroupId
+	data.DataFields = meter.DataFields
+	data.DerivedFields = meter.DerivedFields
+	data.CustomFields = meter.CustomFields
+}
@@ -8,7 +8,10 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"slices"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
@@ -16,6 +19,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -25,6 +29,8 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &MeterResource{}
 var _ resource.ResourceWithImportState = &MeterResource{}
+var _ resource.ResourceWithValidateConfig = &MeterResource{}
+var _ resource.ResourceWithModifyPlan = &MeterResource{}
 
 func NewMeterResource() resource.Resource {
 	return &MeterResource{}
@@ -37,15 +43,41 @@ type MeterResource struct {
 
 // MeterResourceModel describes the resource data model.
 type MeterResourceModel struct {
-	CustomFields  types.Dynamic `tfsdk:"custom_fields"`
-	ProductId     types.String  `tfsdk:"product_id"`
-	GroupId       types.String  `tfsdk:"group_id"`
-	Name          types.String  `tfsdk:"name"`
-	Code          types.String  `tfsdk:"code"`
-	DataFields    types.List    `tfsdk:"data_fields"`
-	DerivedFields types.List    `tfsdk:"derived_fields"`
-	Id            types.String  `tfsdk:"id"`
-	Version       types.Int64   `tfsdk:"version"`
+	CustomFields           types.Dynamic  `tfsdk:"custom_fields"`
+	ProductId              types.String   `tfsdk:"product_id"`
+	ProductCode            types.String   `tfsdk:"product_code"`
+	GroupId                types.String   `tfsdk:"group_id"`
+	Name                   types.String   `tfsdk:"name"`
+	Code                   types.String   `tfsdk:"code"`
+	DataFields             types.List     `tfsdk:"data_fields"`
+	DerivedFields          types.List     `tfsdk:"derived_fields"`
+	ForceDestroy           types.Bool     `tfsdk:"force_destroy"`
+	AllowUnknownCategories types.Bool     `tfsdk:"allow_unknown_categories"`
+	ValidateCalculations   types.Bool     `tfsdk:"validate_calculations"`
+	Id                     types.String   `tfsdk:"id"`
+	Version                types.Int64    `tfsdk:"version"`
+	Timeouts               timeouts.Value `tfsdk:"timeouts"`
+}
+
+// meterFieldCategories are the data and derived field categories known to the
+// provider. Others are rejected unless allow_unknown_categories is set.
+var meterFieldCategories = []string{
+	"WHO",
+	"WHAT",
+	"WHERE",
+	"OTHER",
+	"METADATA",
+	"MEASURE",
+	"INCOME",
+	"COST",
+}
+
+// meterNumericFieldCategories are the field categories holding numeric values,
+// which must have a unit.
+var meterNumericFieldCategories = []string{
+	"MEASURE",
+	"INCOME",
+	"COST",
 }
 
 var dataFieldsType = schema.NestedAttributeObject{
@@ -53,18 +85,6 @@ var dataFieldsType = schema.NestedAttributeObject{
 		"category": schema.StringAttribute{
 			MarkdownDescription: "The field type, which defines the type of data collected in the field.",
 			Required:            true,
-			Validators: []validator.String{
-				stringvalidator.OneOf(
-					"WHO",
-					"WHAT",
-					"WHERE",
-					"OTHER",
-					"METADATA",
-					"MEASURE",
-					"INCOME",
-					"COST",
-				),
-			},
 		},
 		"code": schema.StringAttribute{
 			MarkdownDescription: "Short code to identify the field",
@@ -82,7 +102,7 @@ var dataFieldsType = schema.NestedAttributeObject{
 			},
 		},
 		"unit": schema.StringAttribute{
-			MarkdownDescription: "The units to measure the data with. Should conform to Unified Code for Units of Measure (UCUM). Required only for numeric field categories.",
+			MarkdownDescription: "The units to measure the data with. Should conform to Unified Code for Units of Measure (UCUM). Required for the numeric MEASURE, INCOME and COST categories.",
 			Optional:            true,
 			Validators: []validator.String{
 				stringvalidator.LengthBetween(1, 50),
@@ -96,18 +116,6 @@ var derivedFieldsType = schema.NestedAttributeObject{
 		"category": schema.StringAttribute{
 			MarkdownDescription: "The field type, which defines the type of data collected in the field.",
 			Required:            true,
-			Validators: []validator.String{
-				stringvalidator.OneOf(
-					"WHO",
-					"WHAT",
-					"WHERE",
-					"OTHER",
-					"METADATA",
-					"MEASURE",
-					"INCOME",
-					"COST",
-				),
-			},
 		},
 		"code": schema.StringAttribute{
 			MarkdownDescription: "Short code to identify the field",
@@ -125,7 +133,7 @@ var derivedFieldsType = schema.NestedAttributeObject{
 			},
 		},
 		"unit": schema.StringAttribute{
-			MarkdownDescription: "The units to measure the data with. Should conform to Unified Code for Units of Measure (UCUM). Required only for numeric field categories.",
+			MarkdownDescription: "The units to measure the data with. Should conform to Unified Code for Units of Measure (UCUM). Required for the numeric MEASURE, INCOME and COST categories.",
 			Optional:            true,
 			Validators: []validator.String{
 				stringvalidator.LengthBetween(1, 50),
@@ -152,12 +160,24 @@ func (r *MeterResource) Schema(ctx context.Context, req resource.SchemaRequest,
 
 		Attributes: map[string]schema.Attribute{
 			"custom_fields": schema.DynamicAttribute{
-				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be either a string or a number.",
+				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be a string, a number or a boolean.",
 				Required:            true,
 			},
 			"product_id": schema.StringAttribute{
 				MarkdownDescription: "UUID of the product the Meter belongs to. (Optional) - if left blank, the Meter is global.",
 				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					productIdFromCode(),
+				},
+			},
+			"product_code": schema.StringAttribute{
+				MarkdownDescription: "Code of the product the Meter belongs to, resolved to `product_id` at apply time. Conflicts with `product_id`. Neither is set for a global Meter, which belongs to no product.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("product_id")),
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
 			"group_id": schema.StringAttribute{
 				MarkdownDescription: "UUID of the group the Meter belongs to. (Optional).",
@@ -187,13 +207,27 @@ func (r *MeterResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				},
 			},
 			"derived_fields": schema.ListNestedAttribute{
-				MarkdownDescription: "Used to submit usage data values for ingest into the platform that are the result of a calculation performed on dataFields, customFields, or system Timestamp fields. Raw usage data is not submitted using derivedFields. Maximum 15 per Meter.",
+				MarkdownDescription: "Used to submit usage data values for ingest into the platform that are the result of a calculation performed on dataFields, customFields, or system Timestamp fields. Raw usage data is not submitted using derivedFields. Maximum 15 per Meter. Derived fields are sent in the declared order, and a calculation can only reference the derived fields before it.",
 				Required:            true,
 				NestedObject:        derivedFieldsType,
 				Validators: []validator.List{
 					listvalidator.SizeAtMost(15),
 				},
 			},
+			"allow_unknown_categories": schema.BoolAttribute{
+				MarkdownDescription: "When true, data and derived field categories not known to the provider produce a warning instead of an error, allowing categories newly added to m3ter to be used.",
+				Optional:            true,
+			},
+			"validate_calculations": schema.BoolAttribute{
+				MarkdownDescription: "When false, derived field calculations are not checked for references to fields the Meter does not define, or to derived fields defined after them. The check only produces warnings, and defaults to true.",
+				Optional:            true,
+			},
+			"force_destroy": schema.BoolAttribute{
+				MarkdownDescription: "When true, any Aggregations using the Meter, and any Pricings using those Aggregations, are deleted before the Meter is destroyed. Otherwise destroying a Meter that is in use fails, naming the Aggregations that use it.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
 			"id": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "Meter identifier",
@@ -206,6 +240,9 @@ func (r *MeterResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				MarkdownDescription: "Meter version",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -229,6 +266,155 @@ func (r *MeterResource) Configure(ctx context.Context, req resource.ConfigureReq
 	r.client = client
 }
 
+func (r *MeterResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data MeterResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateFields := func(fields types.List, attributeName string) {
+		if fields.IsUnknown() || fields.IsNull() {
+			return
+		}
+
+		for i, field := range fields.Elements() {
+			field, ok := field.(types.Object)
+			if !ok || field.IsUnknown() || field.IsNull() {
+				continue
+			}
+
+			category, ok := field.Attributes()["category"].(types.String)
+			if !ok || category.IsUnknown() || category.IsNull() {
+				continue
+			}
+
+			unit, _ := field.Attributes()["unit"].(types.String)
+			if slices.Contains(meterNumericFieldCategories, category.ValueString()) && unit.IsNull() {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(attributeName).AtListIndex(i).AtName("unit"),
+					"Missing field unit",
+					fmt.Sprintf("Fields of the numeric %s category must have a unit.", category.ValueString()),
+				)
+			}
+
+			if slices.Contains(meterFieldCategories, category.ValueString()) {
+				continue
+			}
+
+			attributePath := path.Root(attributeName).AtListIndex(i).AtName("category")
+			summary := "Unknown field category"
+			detail := fmt.Sprintf("Category %s is not one of %s.", category.ValueString(), strings.Join(meterFieldCategories, ", "))
+			if data.AllowUnknownCategories.ValueBool() {
+				resp.Diagnostics.AddAttributeWarning(attributePath, summary, detail)
+			} else {
+				resp.Diagnostics.AddAttributeError(attributePath, summary, detail+" Set allow_unknown_categories to use categories not yet known to the provider.")
+			}
+		}
+	}
+
+	validateFields(data.DataFields, "data_fields")
+	validateFields(data.DerivedFields, "derived_fields")
+
+	if data.ValidateCalculations.IsNull() || data.ValidateCalculations.ValueBool() {
+		validateCalculations(data, &resp.Diagnostics)
+	}
+}
+
+var (
+	// calculationStringRegexp matches string literals in a calculation
+	calculationStringRegexp = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`)
+	// calculationIdentifierRegexp matches identifiers in a calculation
+	calculationIdentifierRegexp = regexp.MustCompile(`[A-Za-z_]\w*`)
+	// calculationKeywords are identifiers that aren't field references
+	calculationKeywords = []string{"true", "false", "null", "and", "or", "not", "if", "then", "else", "in", "ts"}
+)
+
+func isWordByte(b byte) bool {
+	return b == '_' || ('0' <= b && b <= '9') || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
+
+// validateCalculations warns about derived field calculations referencing
+// identifiers which are not fields of the meter, as this is most likely a typo.
+// Calculations can be too complex to check exactly, so this is best-effort and
+// never fails validation.
+func validateCalculations(data MeterResourceModel, diagnostics *diag.Diagnostics) {
+	if data.DerivedFields.IsUnknown() || data.DerivedFields.IsNull() {
+		return
+	}
+
+	var codes []string
+	// derivedIndex is the position of each derived field, as a derived field
+	// can only reference the derived fields defined before it
+	derivedIndex := make(map[string]int)
+	for j, fields := range []types.List{data.DataFields, data.DerivedFields} {
+		if fields.IsUnknown() {
+			return
+		}
+		for k, field := range fields.Elements() {
+			field, ok := field.(types.Object)
+			if !ok || field.IsUnknown() {
+				return
+			}
+			code, ok := field.Attributes()["code"].(types.String)
+			if !ok || code.IsUnknown() {
+				return
+			}
+			codes = append(codes, code.ValueString())
+			if j == 1 {
+				derivedIndex[code.ValueString()] = k
+			}
+		}
+	}
+
+	for i, field := range data.DerivedFields.Elements() {
+		field, ok := field.(types.Object)
+		if !ok || field.IsNull() {
+			continue
+		}
+		calculation, ok := field.Attributes()["calculation"].(types.String)
+		if !ok || calculation.IsUnknown() || calculation.IsNull() {
+			continue
+		}
+
+		expression := calculationStringRegexp.ReplaceAllString(calculation.ValueString(), "")
+		var unknown, later []string
+		for _, match := range calculationIdentifierRegexp.FindAllStringIndex(expression, -1) {
+			identifier := expression[match[0]:match[1]]
+			before := strings.TrimRight(expression[:match[0]], " \t")
+			after := strings.TrimLeft(expression[match[1]:], " \t")
+			// Numbers, function calls and dotted paths such as custom field
+			// references are not checked
+			if strings.HasSuffix(before, ".") || (match[0] > 0 && isWordByte(expression[match[0]-1])) ||
+				strings.HasPrefix(after, "(") || strings.HasPrefix(after, ".") ||
+				slices.Contains(calculationKeywords, strings.ToLower(identifier)) {
+				continue
+			}
+			if !slices.Contains(codes, identifier) && !slices.Contains(unknown, identifier) {
+				unknown = append(unknown, identifier)
+			}
+			if index, ok := derivedIndex[identifier]; ok && index >= i && !slices.Contains(later, identifier) {
+				later = append(later, identifier)
+			}
+		}
+
+		if len(unknown) > 0 {
+			diagnostics.AddAttributeWarning(
+				path.Root("derived_fields").AtListIndex(i).AtName("calculation"),
+				"Calculation references unknown fields",
+				fmt.Sprintf("The calculation references %s, which are not fields of the Meter. Set validate_calculations to false to disable this check.", strings.Join(unknown, ", ")),
+			)
+		}
+		if len(later) > 0 {
+			diagnostics.AddAttributeWarning(
+				path.Root("derived_fields").AtListIndex(i).AtName("calculation"),
+				"Calculation references later derived fields",
+				fmt.Sprintf("The calculation references %s, which are not defined before this derived field. Derived fields are sent to m3ter in the declared order and can only reference the derived fields before them, so move this derived field after them. Set validate_calculations to false to disable this check.", strings.Join(later, ", ")),
+			)
+		}
+	}
+}
+
 func (r *MeterResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	genericCreate(ctx, req, resp, r.client, "/meters", "meter", r.read, r.write)
 }
@@ -242,39 +428,68 @@ func (r *MeterResource) Update(ctx context.Context, req resource.UpdateRequest,
 }
 
 func (r *MeterResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	genericDelete[MeterResourceModel](ctx, req, resp, r.client, "/meters", "meter")
-}
+	ctx, cancel := withOperationTimeout(ctx, req.State, "delete", &resp.Diagnostics)
+	defer cancel()
+	ctx, logCalls := countAPICalls(ctx, "delete", "meter")
+	defer logCalls()
+
+	var data MeterResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-func (r *MeterResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	var restData map[string]any
-	err := r.client.execute(ctx, "GET", "/meters/"+url.PathEscape(req.ID), nil, nil, &restData)
-	if sc, ok := err.(*statusCodeError); ok && sc.StatusCode == 404 {
-		urlValues := url.Values{}
-		urlValues.Set("pageSize", "1")
-		urlValues.Set("codes", req.ID)
-
-		var meterListResponse struct {
-			Data []struct {
-				Id      string `json:"id"`
-				Code    string `json:"code"`
-				Version int64  `json:"version"`
-			} `json:"data"`
-			NextToken string `json:"next_token"`
-		}
-		err := r.client.execute(ctx, "GET", "/meters", nil, nil, &meterListResponse)
+	if data.ForceDestroy.ValueBool() {
+		aggregationIds, err := findDependents(ctx, r.client, "/aggregations", "meterId", data.Id.ValueString())
 		if err != nil {
-			resp.Diagnostics.AddError("Failed to list meters", err.Error())
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list aggregations for meter, got error: %s", err))
 			return
 		}
-		for _, meter := range meterListResponse.Data {
-			if meter.Code == req.ID {
-				resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), meter.Id)...)
+
+		for _, aggregationId := range aggregationIds {
+			deleteAggregationPricings(ctx, r.client, aggregationId, &resp.Diagnostics)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			err := r.client.execute(ctx, "DELETE", "/aggregations/"+url.PathEscape(aggregationId), nil, nil, nil)
+			if sc, ok := err.(*statusCodeError); ok && sc.StatusCode == 404 {
+				continue
+			}
+			if err != nil {
+				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete aggregation %s, got error: %s", aggregationId, err))
 				return
 			}
 		}
-		resp.Diagnostics.AddError("Meter not found", "The meter with code "+req.ID+" does not exist.")
 	}
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+
+	err := r.client.execute(ctx, "DELETE", "/meters/"+url.PathEscape(data.Id.ValueString()), nil, nil, nil)
+	// The meter was already deleted outside Terraform
+	if sc, ok := err.(*statusCodeError); ok && sc.StatusCode == 404 {
+		return
+	}
+	if isConflict(err) {
+		// The aggregations are only looked up to explain why the meter can't
+		// be deleted
+		aggregationIds, listErr := findDependents(ctx, r.client, "/aggregations", "meterId", data.Id.ValueString())
+		if listErr != nil || len(aggregationIds) == 0 {
+			resp.Diagnostics.AddError("Meter in use", fmt.Sprintf("Meter %s is in use. Delete its dependents first or set force_destroy to delete them along with the meter. Got error: %s", data.Id.ValueString(), err))
+			return
+		}
+		resp.Diagnostics.AddError("Meter in use", fmt.Sprintf("Meter %s is used by aggregations %s. Delete them first or set force_destroy to delete them along with the meter. Got error: %s", data.Id.ValueString(), strings.Join(aggregationIds, ", "), err))
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete meter, got error: %s", err))
+	}
+}
+
+func (r *MeterResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	genericImportState(ctx, req, resp, r.client, "/meters", "meter", []string{"code"})
+}
+
+func (r *MeterResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkRequiredCustomFields(ctx, r.client, req.Plan, &resp.Diagnostics)
 }
 
 func (r *MeterResource) read(ctx context.Context, data *MeterResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
@@ -287,9 +502,18 @@ func (r *MeterResource) read(ctx context.Context, data *MeterResourceModel, rest
 	m.to("version", &data.Version)
 	m.customFieldsTo(&data.CustomFields)
 	m.to("productId", &data.ProductId)
+	// The API omits the product of global entities
+	if data.ProductId.IsUnknown() {
+		data.ProductId = types.StringNull()
+	}
 	m.to("groupId", &data.GroupId)
 	m.to("name", &data.Name)
 	m.to("code", &data.Code)
+	// The API doesn't store force_destroy, so imported meters start from its
+	// default
+	if data.ForceDestroy.IsNull() {
+		data.ForceDestroy = types.BoolValue(false)
+	}
 	m.listTo("dataFields", &data.DataFields, dataFieldsType.Type(), func(v any) (attr.Value, diag.Diagnostics) {
 		mv, ok := v.(map[string]any)
 		if !ok {
@@ -315,14 +539,13 @@ func (r *MeterResource) read(ctx context.Context, data *MeterResourceModel, rest
 		}
 		attrs["name"] = types.StringValue(name)
 
-		if _, ok := mv["unit"]; ok {
-			unit, ok := mv["unit"].(string)
-			if !ok {
-				return nil, diag.Diagnostics{diag.NewErrorDiagnostic("unit must be a string", "expected unit to be a string")}
-			}
-			attrs["unit"] = types.StringValue(unit)
-		} else {
+		switch unit := mv["unit"].(type) {
+		case nil:
 			attrs["unit"] = types.StringNull()
+		case string:
+			attrs["unit"] = types.StringValue(unit)
+		default:
+			return nil, diag.Diagnostics{diag.NewErrorDiagnostic("unit must be a string", "expected unit to be a string")}
 		}
 
 		ts := make(map[string]attr.Type)
@@ -358,14 +581,13 @@ func (r *MeterResource) read(ctx context.Context, data *MeterResourceModel, rest
 		}
 		attrs["name"] = types.StringValue(name)
 
-		if _, ok := mv["unit"]; ok {
-			unit, ok := mv["unit"].(string)
-			if !ok {
-				return nil, diag.Diagnostics{diag.NewErrorDiagnostic("unit must be a string", "expected unit to be a string")}
-			}
-			attrs["unit"] = types.StringValue(unit)
-		} else {
+		switch unit := mv["unit"].(type) {
+		case nil:
 			attrs["unit"] = types.StringNull()
+		case string:
+			attrs["unit"] = types.StringValue(unit)
+		default:
+			return nil, diag.Diagnostics{diag.NewErrorDiagnostic("unit must be a string", "expected unit to be a string")}
 		}
 
 		calculation, ok := mv["calculation"].(string)
@@ -395,6 +617,12 @@ func (r *MeterResource) write(ctx context.Context, data *MeterResourceModel, res
 	m.from(data.Version, "version")
 	m.customFieldsFrom(data.CustomFields)
 	m.from(data.ProductId, "productId")
+	// A global entity is planned with a null product, which clears any
+	// product it was moved from
+	if data.ProductId.IsNull() {
+		delete(m.v, "productId")
+	}
+	resolveProductCode(ctx, r.client, data.ProductCode, restData, diagnostics)
 	m.from(data.GroupId, "groupId")
 	m.from(data.Name, "name")
 	m.from(data.Code, "code")
@@ -440,6 +668,8 @@ func (r *MeterResource) write(ctx context.Context, data *MeterResourceModel, res
 
 		return m, nil
 	})
+	// Derived fields are sent in the declared order, as a calculation can only
+	// reference the derived fields before it
 	m.listFrom(data.DerivedFields, "derivedFields", func(v attr.Value) (any, diag.Diagnostics) {
 		ov, ok := v.(types.Object)
 		if !ok {
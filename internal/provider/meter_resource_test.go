@@ -0,0 +1,176 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func testMeterConfig() map[string]any {
+	return map[string]any{
+		"custom_fields": map[string]any{},
+		"name":          "API calls",
+		"code":          "api_calls",
+		"data_fields": []any{
+			map[string]any{"category": "MEASURE", "code": "calls", "name": "Calls", "unit": "{call}"},
+		},
+		"derived_fields": []any{},
+	}
+}
+
+func TestMeterResourceDeleteInUse(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	state := p.create("m3ter_meter", testMeterConfig())
+	id := attrValue(t, state, "id").(string)
+	aggregationId := api.put("aggregations", map[string]any{"meterId": id})
+	api.handle(func(w http.ResponseWriter, req *fakeRequest) bool {
+		if req.Method != http.MethodDelete || req.Path != "/meters/"+id {
+			return false
+		}
+		writeJSON(w, http.StatusConflict, map[string]any{"message": "meter is used by an aggregation"})
+		return true
+	})
+
+	api.clearRequests()
+	diags := p.destroy("m3ter_meter", state)
+	summary := diagnosticsSummary(diags)
+	if !hasErrors(diags) || !strings.Contains(summary, "Meter in use") || !strings.Contains(summary, aggregationId) || !strings.Contains(summary, "meter is used by an aggregation") {
+		t.Errorf("got diagnostics %q, want the meter reported in use by aggregation %s", summary, aggregationId)
+	}
+
+	// Without force_destroy the meter is deleted first, the aggregations only
+	// being looked up once the API refuses
+	sent := api.sentRequests()
+	if want := []string{"DELETE /meters/" + id, "GET /aggregations"}; !slices.Equal(sent, want) {
+		t.Errorf("sent %v, want %v", sent, want)
+	}
+}
+
+func TestMeterResourceForceDestroy(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	config := testMeterConfig()
+	config["force_destroy"] = true
+	state := p.create("m3ter_meter", config)
+	id := attrValue(t, state, "id").(string)
+	aggregationId := api.put("aggregations", map[string]any{"meterId": id})
+	pricingId := api.put("pricings", map[string]any{"aggregationId": aggregationId})
+
+	if diags := p.destroy("m3ter_meter", state); hasErrors(diags) {
+		t.Fatal(diagnosticsSummary(diags))
+	}
+	for collection, id := range map[string]string{"meters": id, "aggregations": aggregationId, "pricings": pricingId} {
+		if api.get(collection, id) != nil {
+			t.Errorf("%s %s was not deleted", collection, id)
+		}
+	}
+}
+
+func TestMeterResourceNullFields(t *testing.T) {
+	api := newFakeAPI(t)
+	// The API returns null for an empty list of derived fields and for a
+	// data field without a unit
+	api.onWrite = func(collection string, entity map[string]any) {
+		if collection != "meters" {
+			return
+		}
+		entity["derivedFields"] = nil
+		for _, field := range entity["dataFields"].([]any) {
+			field := field.(map[string]any)
+			if _, ok := field["unit"]; !ok {
+				field["unit"] = nil
+			}
+		}
+	}
+	p := newTestProvider(t, api, nil)
+
+	config := testMeterConfig()
+	config["data_fields"] = []any{
+		map[string]any{"category": "MEASURE", "code": "calls", "name": "Calls", "unit": "{call}"},
+		map[string]any{"category": "WHO", "code": "user", "name": "User"},
+	}
+	state := p.create("m3ter_meter", config)
+	state = p.read("m3ter_meter", state)
+	if v := attrValue(t, state, "data_fields.1.unit"); v != nil {
+		t.Errorf("data_fields.1.unit = %v, want null", v)
+	}
+	if v := attrValue(t, state, "derived_fields"); v != 0 {
+		t.Errorf("derived_fields has %v elements, want 0", v)
+	}
+	p.assertNoChanges("m3ter_meter", state, config)
+}
+
+func TestMeterResourceProductId(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+	productId := api.put("products", map[string]any{"code": "storage"})
+	otherProductId := api.put("products", map[string]any{"code": "compute"})
+
+	// A global meter plans a null product rather than an unknown one
+	config := testMeterConfig()
+	planned, diags := p.plan("m3ter_meter", p.null("m3ter_meter"), config)
+	p.checkDiagnostics("plan", diags)
+	if v := attrValue(t, planned, "product_id"); v != nil {
+		t.Errorf("planned product_id = %v, want null", v)
+	}
+	state := p.create("m3ter_meter", config)
+	config["name"] = "Renamed"
+	planned, diags = p.plan("m3ter_meter", state, config)
+	p.checkDiagnostics("plan", diags)
+	if v := attrValue(t, planned, "product_id"); v != nil {
+		t.Errorf("planned product_id = %v on update, want null", v)
+	}
+
+	// A product code is resolved on apply, and kept while it doesn't change
+	config["product_code"] = "storage"
+	state = p.update("m3ter_meter", state, config)
+	if v := attrValue(t, state, "product_id"); v != productId {
+		t.Errorf("product_id = %v, want %s resolved from the code", v, productId)
+	}
+	config["name"] = "Renamed again"
+	planned, diags = p.plan("m3ter_meter", state, config)
+	p.checkDiagnostics("plan", diags)
+	if v := attrValue(t, planned, "product_id"); v != productId {
+		t.Errorf("planned product_id = %v, want %s kept", v, productId)
+	}
+	config["product_code"] = "compute"
+	planned, diags = p.plan("m3ter_meter", state, config)
+	p.checkDiagnostics("plan", diags)
+	if v := attrValue(t, planned, "product_id"); v != unknown {
+		t.Errorf("planned product_id = %v, want it unknown until the new code is resolved", v)
+	}
+	state = p.update("m3ter_meter", state, config)
+	if v := attrValue(t, state, "product_id"); v != otherProductId {
+		t.Errorf("product_id = %v, want %s", v, otherProductId)
+	}
+	state = p.read("m3ter_meter", state)
+	p.assertNoChanges("m3ter_meter", state, config)
+}
+
+func TestMeterResourceMakeGlobal(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+	api.put("products", map[string]any{"code": "storage"})
+
+	config := testMeterConfig()
+	config["product_code"] = "storage"
+	state := p.create("m3ter_meter", config)
+
+	// Removing the product code moves the meter out of the product
+	delete(config, "product_code")
+	state = p.update("m3ter_meter", state, config)
+	id := attrValue(t, state, "id").(string)
+	if v, ok := api.get("meters", id)["productId"]; ok {
+		t.Errorf("stored productId = %v, want it cleared", v)
+	}
+	if v := attrValue(t, state, "product_id"); v != nil {
+		t.Errorf("product_id = %v, want null", v)
+	}
+}
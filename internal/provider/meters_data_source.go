@@ -0,0 +1,147 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &MetersDataSource{}
+var _ datasource.DataSourceWithConfigure = &MetersDataSource{}
+
+func NewMetersDataSource() datasource.DataSource {
+	r := &MetersDataSource{}
+	r.genericListDataSource = genericListDataSource[MetersDataSourceModel, metersEntryModel]{
+		typeName:        "meters",
+		path:            "/meters",
+		name:            "meter",
+		orderBy:         "code",
+		entryAttributes: metersEntryAttributes,
+		query: func(ctx context.Context, data *MetersDataSourceModel, diagnostics *diag.Diagnostics) url.Values {
+			query := url.Values{}
+			if productId := data.ProductId.ValueString(); productId != "" {
+				query.Set("productId", productId)
+			}
+			return query
+		},
+		match: func(data *MetersDataSourceModel, restData map[string]any) bool {
+			id, _ := restData["productId"].(string)
+			return data.ProductId.ValueString() == "" || id == data.ProductId.ValueString()
+		},
+		extraQuery: func(data *MetersDataSourceModel) types.Map {
+			return data.ExtraQuery
+		},
+		entries: func(data *MetersDataSourceModel) *types.List {
+			return &data.Meters
+		},
+		read: r.read,
+	}
+	return r
+}
+
+// MetersDataSource defines the data source implementation.
+type MetersDataSource struct {
+	genericListDataSource[MetersDataSourceModel, metersEntryModel]
+}
+
+type MetersDataSourceModel struct {
+	ProductId  types.String `tfsdk:"product_id"`
+	Meters     types.List   `tfsdk:"meters"`
+	ExtraQuery types.Map    `tfsdk:"extra_query"`
+}
+
+type metersEntryModel struct {
+	Id            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	Code          types.String `tfsdk:"code"`
+	ProductId     types.String `tfsdk:"product_id"`
+	DataFields    types.List   `tfsdk:"data_fields"`
+	DerivedFields types.List   `tfsdk:"derived_fields"`
+	Version       types.Int64  `tfsdk:"version"`
+}
+
+var metersEntryAttributes = map[string]schema.Attribute{
+	"id": schema.StringAttribute{
+		MarkdownDescription: "Meter identifier",
+		Computed:            true,
+	},
+	"name": schema.StringAttribute{
+		MarkdownDescription: "Descriptive name for the Meter.",
+		Computed:            true,
+	},
+	"code": schema.StringAttribute{
+		MarkdownDescription: "A unique short code to identify the Meter.",
+		Computed:            true,
+	},
+	"product_id": schema.StringAttribute{
+		MarkdownDescription: "UUID of the Product the Meter belongs to, if any.",
+		Computed:            true,
+	},
+	"data_fields": schema.ListNestedAttribute{
+		MarkdownDescription: "The fields of raw usage data collected by the Meter.",
+		Computed:            true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: meterDataSourceFieldAttributes,
+		},
+	},
+	"derived_fields": schema.ListNestedAttribute{
+		MarkdownDescription: "The fields calculated by the Meter from the data fields.",
+		Computed:            true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: meterDataSourceDerivedFieldAttributes,
+		},
+	},
+	"version": schema.Int64Attribute{
+		MarkdownDescription: "Meter version",
+		Computed:            true,
+	},
+}
+
+func (r *MetersDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Meters data source. Lists every meter, or those of a product.",
+
+		Attributes: map[string]schema.Attribute{
+			"product_id": schema.StringAttribute{
+				MarkdownDescription: "UUID of the Product to list the Meters of. Every Meter is listed when not set.",
+				Optional:            true,
+			},
+			"meters": schema.ListNestedAttribute{
+				MarkdownDescription: "The Meters found, ordered by code.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: metersEntryAttributes,
+				},
+			},
+			"extra_query": extraQueryAttribute,
+		},
+	}
+}
+
+func (r *MetersDataSource) read(ctx context.Context, entry *metersEntryModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	// The meter is mapped the same way as by the resource
+	meter := MeterResourceModel{
+		CustomFields:  types.DynamicNull(),
+		DataFields:    types.ListNull(dataFieldsType.Type()),
+		DerivedFields: types.ListNull(derivedFieldsType.Type()),
+	}
+	(&MeterResource{}).read(ctx, &meter, restData, diagnostics)
+
+	*entry = metersEntryModel{
+		Id:            meter.Id,
+		Name:          meter.Name,
+		Code:          meter.Code,
+		ProductId:     meter.ProductId,
+		DataFields:    meter.DataFields,
+		DerivedFields: meter.DerivedFields,
+		Version:       meter.Version,
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMetersDataSourceProduct(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+	productId := api.put("products", map[string]any{"code": "storage"})
+	api.put("meters", map[string]any{"name": "Writes", "code": "writes", "productId": productId})
+	api.put("meters", map[string]any{"name": "Global", "code": "global"})
+	readsId := api.put("meters", map[string]any{
+		"name":       "Reads",
+		"code":       "reads",
+		"productId":  productId,
+		"dataFields": []any{map[string]any{"category": "WHO", "code": "user", "name": "User"}},
+	})
+
+	// Every meter is listed, ordered by code
+	state := p.readDataSource("m3ter_meters", map[string]any{})
+	if v := attrValue(t, state, "meters"); v != 3 {
+		t.Fatalf("listed %v meters, want 3", v)
+	}
+	for i, code := range []string{"global", "reads", "writes"} {
+		if v := attrValue(t, state, fmt.Sprintf("meters.%d.code", i)); v != code {
+			t.Errorf("meter %d has code %v, want %s", i, v, code)
+		}
+	}
+	if v := attrValue(t, state, "meters.0.product_id"); v != nil {
+		t.Errorf("product_id = %v for the global meter, want null", v)
+	}
+
+	// The meters of a product are listed with the API's filter
+	api.clearRequests()
+	state = p.readDataSource("m3ter_meters", map[string]any{"product_id": productId})
+	if v := attrValue(t, state, "meters"); v != 2 {
+		t.Fatalf("listed %v meters of the product, want 2", v)
+	}
+	if v := attrValue(t, state, "meters.0.id"); v != readsId {
+		t.Errorf("first meter id = %v, want %s", v, readsId)
+	}
+	if v := attrValue(t, state, "meters.0.data_fields.0.code"); v != "user" {
+		t.Errorf("data field code = %v, want user", v)
+	}
+	lists := api.requestsTo("GET", "/meters")
+	if len(lists) != 1 || lists[0].Query.Get("productId") != productId {
+		t.Errorf("the meters were not listed filtered by product")
+	}
+}
@@ -6,7 +6,12 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -17,11 +22,14 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &NotificationResource{}
 var _ resource.ResourceWithImportState = &NotificationResource{}
+var _ resource.ResourceWithValidateConfig = &NotificationResource{}
+var _ resource.ResourceWithModifyPlan = &NotificationResource{}
 
 func NewNotificationResource() resource.Resource {
 	return &NotificationResource{}
@@ -34,15 +42,16 @@ type NotificationResource struct {
 
 // NotificationResourceModel describes the resource data model.
 type NotificationResourceModel struct {
-	Name            types.String `tfsdk:"name"`
-	Description     types.String `tfsdk:"description"`
-	Active          types.Bool   `tfsdk:"active"`
-	AlwaysFireEvent types.Bool   `tfsdk:"always_fire_event"`
-	Calculation     types.String `tfsdk:"calculation"`
-	Code            types.String `tfsdk:"code"`
-	EventName       types.String `tfsdk:"event_name"`
-	Id              types.String `tfsdk:"id"`
-	Version         types.Int64  `tfsdk:"version"`
+	Name            types.String   `tfsdk:"name"`
+	Description     types.String   `tfsdk:"description"`
+	Active          types.Bool     `tfsdk:"active"`
+	AlwaysFireEvent types.Bool     `tfsdk:"always_fire_event"`
+	Calculation     types.String   `tfsdk:"calculation"`
+	Code            types.String   `tfsdk:"code"`
+	EventName       types.String   `tfsdk:"event_name"`
+	Id              types.String   `tfsdk:"id"`
+	Version         types.Int64    `tfsdk:"version"`
+	Timeouts        timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *NotificationResourceModel) GetId() types.String {
@@ -89,7 +98,7 @@ func (r *NotificationResource) Schema(ctx context.Context, req resource.SchemaRe
 				},
 			},
 			"calculation": schema.StringAttribute{
-				MarkdownDescription: "A logical expression that that is evaluated to a Boolean. If it evaluates as True, a Notification for the Event is created and sent to the configured destination. Calculations can reference numeric, string, and boolean Event fields.",
+				MarkdownDescription: "A logical expression that that is evaluated to a Boolean. If it evaluates as True, a Notification for the Event is created and sent to the configured destination. Calculations can reference numeric, string, and boolean Event fields. Required unless `always_fire_event` is true. References to `new` and `old` Event fields are checked against the fields of the Event when planning, warning about unknown fields.",
 				Optional:            true,
 			},
 			"code": schema.StringAttribute{
@@ -118,6 +127,9 @@ func (r *NotificationResource) Schema(ctx context.Context, req resource.SchemaRe
 				MarkdownDescription: "Notification version",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -141,6 +153,115 @@ func (r *NotificationResource) Configure(ctx context.Context, req resource.Confi
 	r.client = client
 }
 
+func (r *NotificationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data NotificationResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.AlwaysFireEvent.IsUnknown() || data.AlwaysFireEvent.ValueBool() {
+		return
+	}
+
+	if data.Calculation.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("calculation"),
+			"Missing calculation",
+			"A calculation is required unless always_fire_event is true, otherwise the notification would never be sent.",
+		)
+	}
+}
+
+// notificationFieldRegexp matches references to the fields of the event
+// before (old) and after (new) the change that triggered it
+var notificationFieldRegexp = regexp.MustCompile(`\b(new|old)\.([A-Za-z_]\w*)`)
+
+func (r *NotificationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check when destroying, or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var data NotificationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.EventName.IsUnknown() || data.Calculation.IsUnknown() || data.Calculation.IsNull() {
+		return
+	}
+
+	// Only check calculations when they or their event change
+	if !req.State.Raw.IsNull() {
+		var state NotificationResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if state.EventName.Equal(data.EventName) && state.Calculation.Equal(data.Calculation) {
+			return
+		}
+	}
+
+	fields, err := r.eventFields(ctx, data.EventName.ValueString())
+	if err != nil || fields == nil {
+		// The check is best-effort, so the notification is still planned
+		// when the events metadata is unavailable
+		tflog.Debug(ctx, "Skipping notification calculation check, event fields unavailable", map[string]any{
+			"event_name": data.EventName.ValueString(),
+			"error":      fmt.Sprint(err),
+		})
+		return
+	}
+
+	expression := calculationStringRegexp.ReplaceAllString(data.Calculation.ValueString(), "")
+	var unknown []string
+	for _, match := range notificationFieldRegexp.FindAllStringSubmatch(expression, -1) {
+		reference, field := match[0], match[2]
+		if slices.Contains(fields, reference) || slices.Contains(fields, field) || slices.Contains(unknown, reference) {
+			continue
+		}
+		unknown = append(unknown, reference)
+	}
+
+	if len(unknown) > 0 {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("calculation"),
+			"Calculation references unknown event fields",
+			fmt.Sprintf("The calculation references %s, which are not fields of the %s event. Available fields are %s.", strings.Join(unknown, ", "), data.EventName.ValueString(), strings.Join(fields, ", ")),
+		)
+	}
+}
+
+// eventFields returns the fields of the named event, or nil if the event is
+// not known.
+func (r *NotificationResource) eventFields(ctx context.Context, eventName string) ([]string, error) {
+	query := url.Values{}
+	query.Set("eventName", eventName)
+
+	var response struct {
+		Events map[string]map[string]any `json:"events"`
+	}
+	err := r.client.execute(ctx, "GET", "/events/fields", query, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	eventFields, ok := response.Events[eventName]
+	if !ok {
+		return nil, nil
+	}
+
+	fields := make([]string, 0, len(eventFields))
+	for field := range eventFields {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+	return fields, nil
+}
+
 func (r *NotificationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	genericCreate(ctx, req, resp, r.client, "/notifications/configurations", "notification", r.read, r.write)
 }
@@ -0,0 +1,186 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &OrganizationConfigDataSource{}
+var _ datasource.DataSourceWithConfigure = &OrganizationConfigDataSource{}
+
+func NewOrganizationConfigDataSource() datasource.DataSource {
+	return &OrganizationConfigDataSource{}
+}
+
+// OrganizationConfigDataSource defines the data source implementation.
+type OrganizationConfigDataSource struct {
+	client *m3terClient
+}
+
+func (r *OrganizationConfigDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_organization_config"
+}
+
+func (r *OrganizationConfigDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Organization config data source. Reads the organization config without managing it.",
+
+		Attributes: map[string]schema.Attribute{
+			"timezone": schema.StringAttribute{
+				MarkdownDescription: "The time zone used for the generated Bills.",
+				Computed:            true,
+			},
+			"year_epoch": schema.StringAttribute{
+				MarkdownDescription: "The billing cycle date for Accounts that are billed yearly.",
+				Computed:            true,
+			},
+			"month_epoch": schema.StringAttribute{
+				MarkdownDescription: "The billing cycle date for Accounts that are billed monthly.",
+				Computed:            true,
+			},
+			"week_epoch": schema.StringAttribute{
+				MarkdownDescription: "The billing cycle date for Accounts that are billed weekly.",
+				Computed:            true,
+			},
+			"day_epoch": schema.StringAttribute{
+				MarkdownDescription: "The billing cycle date for Accounts that are billed daily.",
+				Computed:            true,
+			},
+			"currency": schema.StringAttribute{
+				MarkdownDescription: "The currency code for the Organization. For example: USD, GBP, or EUR.",
+				Computed:            true,
+			},
+			"currency_conversions": schema.SetNestedAttribute{
+				MarkdownDescription: "Currency conversion rates from pricing currency to billing currency",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"from": schema.StringAttribute{
+							MarkdownDescription: "Currency to convert from. For example: GBP.",
+							Computed:            true,
+						},
+						"to": schema.StringAttribute{
+							MarkdownDescription: "Currency to convert to. For example: USD.",
+							Computed:            true,
+						},
+						"multiplier": schema.Float64Attribute{
+							MarkdownDescription: "Conversion rate between currencies.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"days_before_bill_due": schema.Int32Attribute{
+				MarkdownDescription: "The number of days after the Bill generation date shown on Bills as the due date.",
+				Computed:            true,
+			},
+			"scheduled_bill_interval": schema.Float64Attribute{
+				MarkdownDescription: "The interval for updating bills, 0 when scheduled updates are disabled.",
+				Computed:            true,
+			},
+			"standing_charge_bill_in_advance": schema.BoolAttribute{
+				MarkdownDescription: "Whether the Standing Charge is billed in advance.",
+				Computed:            true,
+			},
+			"commitment_fee_bill_in_advance": schema.BoolAttribute{
+				MarkdownDescription: "Whether the Commitment Fee is billed in advance.",
+				Computed:            true,
+			},
+			"minimum_spend_bill_in_advance": schema.BoolAttribute{
+				MarkdownDescription: "Whether the Minimum Spend is billed in advance.",
+				Computed:            true,
+			},
+			"external_invoice_date": schema.StringAttribute{
+				MarkdownDescription: "The date on which the external invoice is generated.",
+				Computed:            true,
+			},
+			"suppressed_empty_bills": schema.BoolAttribute{
+				MarkdownDescription: "Whether the generation of empty Bills is suppressed.",
+				Computed:            true,
+			},
+			"consolidate_bills": schema.BoolAttribute{
+				MarkdownDescription: "Whether Bills for different billing frequencies are consolidated onto a single Bill.",
+				Computed:            true,
+			},
+			"default_statement_definition_id": schema.StringAttribute{
+				MarkdownDescription: "The default Statement Definition ID.",
+				Computed:            true,
+			},
+			"sequence_start_number": schema.Int64Attribute{
+				MarkdownDescription: "The sequence start number.",
+				Computed:            true,
+			},
+			"bill_prefix": schema.StringAttribute{
+				MarkdownDescription: "Prefix prepended to the sequence number to form Bill numbers.",
+				Computed:            true,
+			},
+			"auto_generate_statement_mode": schema.StringAttribute{
+				MarkdownDescription: "The auto generate statement mode.",
+				Computed:            true,
+			},
+			"credit_application_order": schema.ListAttribute{
+				MarkdownDescription: "The credit application order.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Organization identifier",
+				Computed:            true,
+			},
+			"version": schema.Int64Attribute{
+				MarkdownDescription: "Organization version",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *OrganizationConfigDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*m3terClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *m3terClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *OrganizationConfigDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data OrganizationConfigResourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	orgData, err := getOrganizationConfig(ctx, r.client)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read organization, got error: %s", err))
+		return
+	}
+
+	readOrganizationConfig(ctx, r.client.organizationID, orgData, &data, &resp.Diagnostics)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -6,10 +6,13 @@ package provider
 import (
 	"context"
 	"fmt"
+	"math"
+	"net/url"
 	"regexp"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
@@ -23,6 +26,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -31,6 +35,7 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &OrganizationConfigResource{}
 var _ resource.ResourceWithImportState = &OrganizationConfigResource{}
+var _ resource.ResourceWithModifyPlan = &OrganizationConfigResource{}
 
 func NewOrganizationConfigResource() resource.Resource {
 	return &OrganizationConfigResource{}
@@ -49,7 +54,7 @@ type OrganizationConfigResourceModel struct {
 	WeekEpoch                    types.String  `tfsdk:"week_epoch"`
 	DayEpoch                     types.String  `tfsdk:"day_epoch"`
 	Currency                     types.String  `tfsdk:"currency"`
-	CurrencyConversions          types.List    `tfsdk:"currency_conversions"`
+	CurrencyConversions          types.Set     `tfsdk:"currency_conversions"`
 	DaysBeforeBillDue            types.Int32   `tfsdk:"days_before_bill_due"`
 	ScheduledBillInterval        types.Float64 `tfsdk:"scheduled_bill_interval"`
 	StandingChargeBillInAdvance  types.Bool    `tfsdk:"standing_charge_bill_in_advance"`
@@ -60,6 +65,7 @@ type OrganizationConfigResourceModel struct {
 	ConsolidateBills             types.Bool    `tfsdk:"consolidate_bills"`
 	DefaultStatementDefinitionId types.String  `tfsdk:"default_statement_definition_id"`
 	SequenceStartNumber          types.Int64   `tfsdk:"sequence_start_number"`
+	BillPrefix                   types.String  `tfsdk:"bill_prefix"`
 	AutoGenerateStatementMode    types.String  `tfsdk:"auto_generate_statement_mode"`
 	CreditApplicationOrder       types.List    `tfsdk:"credit_application_order"`
 	Id                           types.String  `tfsdk:"id"`
@@ -114,7 +120,8 @@ func (r *OrganizationConfigResource) Schema(ctx context.Context, req resource.Sc
 				Optional:            true,
 				Computed:            true,
 				Validators: []validator.String{
-					stringvalidator.RegexMatches(regexp.MustCompile(`\d{4}-\d{2}-\d{2}`), "must be in the format YYYY-MM-DD"),
+					stringvalidator.RegexMatches(regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`), "must be in the format YYYY-MM-DD"),
+					validDate(),
 				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
@@ -125,7 +132,8 @@ func (r *OrganizationConfigResource) Schema(ctx context.Context, req resource.Sc
 				Optional:            true,
 				Computed:            true,
 				Validators: []validator.String{
-					stringvalidator.RegexMatches(regexp.MustCompile(`\d{4}-\d{2}-\d{2}`), "must be in the format YYYY-MM-DD"),
+					stringvalidator.RegexMatches(regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`), "must be in the format YYYY-MM-DD"),
+					validDate(),
 				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
@@ -136,7 +144,8 @@ func (r *OrganizationConfigResource) Schema(ctx context.Context, req resource.Sc
 				Optional:            true,
 				Computed:            true,
 				Validators: []validator.String{
-					stringvalidator.RegexMatches(regexp.MustCompile(`\d{4}-\d{2}-\d{2}`), "must be in the format YYYY-MM-DD"),
+					stringvalidator.RegexMatches(regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`), "must be in the format YYYY-MM-DD"),
+					validDate(),
 				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
@@ -147,7 +156,8 @@ func (r *OrganizationConfigResource) Schema(ctx context.Context, req resource.Sc
 				Optional:            true,
 				Computed:            true,
 				Validators: []validator.String{
-					stringvalidator.RegexMatches(regexp.MustCompile(`\d{4}-\d{2}-\d{2}`), "must be in the format YYYY-MM-DD"),
+					stringvalidator.RegexMatches(regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`), "must be in the format YYYY-MM-DD"),
+					validDate(),
 				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
@@ -164,13 +174,13 @@ func (r *OrganizationConfigResource) Schema(ctx context.Context, req resource.Sc
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"currency_conversions": schema.ListNestedAttribute{
+			"currency_conversions": schema.SetNestedAttribute{
 				MarkdownDescription: "Define currency conversion rates from pricing currency to billing currency",
 				Optional:            true,
 				Computed:            true,
 				NestedObject:        currencyConversionType,
-				PlanModifiers: []planmodifier.List{
-					listplanmodifier.UseStateForUnknown(),
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
 				},
 			},
 			"days_before_bill_due": schema.Int32Attribute{
@@ -241,7 +251,7 @@ func (r *OrganizationConfigResource) Schema(ctx context.Context, req resource.Sc
 				},
 			},
 			"suppressed_empty_bills": schema.BoolAttribute{
-				MarkdownDescription: "Boolean flag that suppresses the generation of empty Bills.",
+				MarkdownDescription: "Boolean flag that suppresses the generation of empty Bills. With `consolidate_bills`, a Bill is only suppressed when every billing frequency consolidated onto it is empty.",
 				Optional:            true,
 				Computed:            true,
 				PlanModifiers: []planmodifier.Bool{
@@ -249,7 +259,7 @@ func (r *OrganizationConfigResource) Schema(ctx context.Context, req resource.Sc
 				},
 			},
 			"consolidate_bills": schema.BoolAttribute{
-				MarkdownDescription: "Boolean flag that consolidates Bills.",
+				MarkdownDescription: "Boolean flag that consolidates Bills for different billing frequencies onto a single Bill.",
 				Optional:            true,
 				Computed:            true,
 				PlanModifiers: []planmodifier.Bool{
@@ -268,10 +278,25 @@ func (r *OrganizationConfigResource) Schema(ctx context.Context, req resource.Sc
 				MarkdownDescription: "The sequence start number.",
 				Optional:            true,
 				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
 				PlanModifiers: []planmodifier.Int64{
 					int64planmodifier.UseStateForUnknown(),
 				},
 			},
+			"bill_prefix": schema.StringAttribute{
+				MarkdownDescription: "Prefix prepended to the sequence number to form Bill numbers. For example: INV-.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(1, 20),
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[^\p{Cc}\s]+$`), "must not contain whitespace or control characters"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 			"auto_generate_statement_mode": schema.StringAttribute{
 				MarkdownDescription: "The auto generate statement mode.",
 				Optional:            true,
@@ -335,14 +360,13 @@ func (r *OrganizationConfigResource) Configure(ctx context.Context, req resource
 func (r *OrganizationConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data OrganizationConfigResourceModel
 
-	var orgData map[string]any
-	err := r.client.execute(ctx, "GET", "/organizationconfig", nil, nil, &orgData)
+	orgData, err := getOrganizationConfig(ctx, r.client)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read organization, got error: %s", err))
 		return
 	}
 
-	r.read(ctx, orgData, &data, &resp.Diagnostics)
+	readOrganizationConfig(ctx, r.client.organizationID, orgData, &data, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -363,7 +387,7 @@ func (r *OrganizationConfigResource) Create(ctx context.Context, req resource.Cr
 		return
 	}
 
-	r.read(ctx, updatedOrgData, &data, &resp.Diagnostics)
+	readOrganizationConfig(ctx, r.client.organizationID, updatedOrgData, &data, &resp.Diagnostics)
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -379,14 +403,13 @@ func (r *OrganizationConfigResource) Read(ctx context.Context, req resource.Read
 		return
 	}
 
-	var orgData map[string]any
-	err := r.client.execute(ctx, "GET", "/organizationconfig", nil, nil, &orgData)
+	orgData, err := getOrganizationConfig(ctx, r.client)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read organization, got error: %s", err))
 		return
 	}
 
-	r.read(ctx, orgData, &data, &resp.Diagnostics)
+	readOrganizationConfig(ctx, r.client.organizationID, orgData, &data, &resp.Diagnostics)
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -395,14 +418,13 @@ func (r *OrganizationConfigResource) Read(ctx context.Context, req resource.Read
 func (r *OrganizationConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var data OrganizationConfigResourceModel
 
-	var orgData map[string]any
-	err := r.client.execute(ctx, "GET", "/organizationconfig", nil, nil, &orgData)
+	orgData, err := getOrganizationConfig(ctx, r.client)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read organization, got error: %s", err))
 		return
 	}
 
-	r.read(ctx, orgData, &data, &resp.Diagnostics)
+	readOrganizationConfig(ctx, r.client.organizationID, orgData, &data, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -423,7 +445,7 @@ func (r *OrganizationConfigResource) Update(ctx context.Context, req resource.Up
 		return
 	}
 
-	r.read(ctx, updatedOrgData, &data, &resp.Diagnostics)
+	readOrganizationConfig(ctx, r.client.organizationID, updatedOrgData, &data, &resp.Diagnostics)
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -438,6 +460,71 @@ func (r *OrganizationConfigResource) ImportState(ctx context.Context, req resour
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+func (r *OrganizationConfigResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan OrganizationConfigResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// With consolidation, empty Bills are only suppressed when every billing
+	// frequency consolidated onto them is empty, which is easily mistaken for
+	// suppression not working
+	var state OrganizationConfigResourceModel
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	if plan.ConsolidateBills.ValueBool() && plan.SuppressedEmptyBills.ValueBool() &&
+		(!state.ConsolidateBills.ValueBool() || !state.SuppressedEmptyBills.ValueBool()) {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("suppressed_empty_bills"),
+			"Empty Bills suppressed with consolidation",
+			"With consolidate_bills enabled, a Bill is only suppressed when all the billing frequencies consolidated onto it are empty. Bills with some empty line items will still be generated.",
+		)
+	}
+
+	// Statements can't be generated with a missing default statement
+	// definition, which would otherwise only surface long after the apply.
+	// This is a best-effort check, so any other failure to look it up is
+	// ignored
+	if r.client != nil && !plan.DefaultStatementDefinitionId.IsUnknown() && plan.DefaultStatementDefinitionId.ValueString() != "" &&
+		!plan.DefaultStatementDefinitionId.Equal(state.DefaultStatementDefinitionId) {
+		var statementDefinition map[string]any
+		err := r.client.execute(ctx, "GET", "/statementdefinitions/"+url.PathEscape(plan.DefaultStatementDefinitionId.ValueString()), nil, nil, &statementDefinition)
+		if sc, ok := err.(*statusCodeError); ok && sc.StatusCode == 404 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("default_statement_definition_id"),
+				"Statement definition not found",
+				fmt.Sprintf("The statement definition %s does not exist.", plan.DefaultStatementDefinitionId.ValueString()),
+			)
+		}
+	}
+
+	// Only changes to an existing organization config are of interest
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	if plan.Currency.IsUnknown() || plan.Currency.IsNull() || state.Currency.ValueString() == "" {
+		return
+	}
+
+	if plan.Currency.ValueString() != state.Currency.ValueString() {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("currency"),
+			"Organization currency change",
+			fmt.Sprintf("The organization currency will change from %s to %s. Existing Bills are not converted, and Accounts, Plans and Pricings relying on the organization currency will be billed in the new currency from now on. Review currency conversions and downstream billing before applying.", state.Currency.ValueString(), plan.Currency.ValueString()),
+		)
+	}
+}
+
 func (r *OrganizationConfigResource) update(ctx context.Context, orgModel map[string]any, resourceModel *OrganizationConfigResourceModel, diagnostics *diag.Diagnostics) {
 	m := &mapper{
 		ctx:         ctx,
@@ -462,6 +549,7 @@ func (r *OrganizationConfigResource) update(ctx context.Context, orgModel map[st
 	m.from(resourceModel.ConsolidateBills, "consolidateBills")
 	m.from(resourceModel.DefaultStatementDefinitionId, "defaultStatementDefinitionId")
 	m.from(resourceModel.SequenceStartNumber, "sequenceStartNumber")
+	m.from(resourceModel.BillPrefix, "billPrefix")
 	m.from(resourceModel.AutoGenerateStatementMode, "autoGenerateStatementMode")
 
 	m.listFrom(resourceModel.CreditApplicationOrder, "creditApplicationOrder", func(v attr.Value) (any, diag.Diagnostics) {
@@ -472,7 +560,7 @@ func (r *OrganizationConfigResource) update(ctx context.Context, orgModel map[st
 		return nil, diag.Diagnostics{diag.NewErrorDiagnostic("cannot map list element, expected string", "")}
 	})
 
-	m.listFrom(resourceModel.CurrencyConversions, "currencyConversions", func(v attr.Value) (any, diag.Diagnostics) {
+	m.setFrom(resourceModel.CurrencyConversions, "currencyConversions", func(v attr.Value) (any, diag.Diagnostics) {
 		if ov, ok := v.(types.Object); ok {
 			attrs := ov.Attributes()
 			from, ok := attrs["from"].(types.String)
@@ -498,14 +586,26 @@ func (r *OrganizationConfigResource) update(ctx context.Context, orgModel map[st
 	})
 }
 
-func (r *OrganizationConfigResource) read(ctx context.Context, orgModel map[string]any, resourceModel *OrganizationConfigResourceModel, diagnostics *diag.Diagnostics) {
+// getOrganizationConfig returns the organization config, a singleton the
+// API may return with no body at all, as a 204. The config is then empty, so
+// reading it keeps the values already in the model, which for a resource are
+// its prior state.
+func getOrganizationConfig(ctx context.Context, client *m3terClient) (map[string]any, error) {
+	orgData := make(map[string]any)
+	err := client.execute(ctx, "GET", "/organizationconfig", nil, nil, &orgData)
+	return orgData, err
+}
+
+// readOrganizationConfig converts the organization config returned by the API
+// into the model shared by the resource and the data source.
+func readOrganizationConfig(ctx context.Context, organizationID string, orgModel map[string]any, resourceModel *OrganizationConfigResourceModel, diagnostics *diag.Diagnostics) {
 	m := &mapper{
 		ctx:         ctx,
 		diagnostics: diagnostics,
 		v:           orgModel,
 	}
 	// convert the json data into the terraform model
-	resourceModel.Id = types.StringValue(r.client.organizationID)
+	resourceModel.Id = types.StringValue(organizationID)
 	m.to("version", &resourceModel.Version)
 	m.to("timezone", &resourceModel.Timezone)
 	m.to("yearEpoch", &resourceModel.YearEpoch)
@@ -514,17 +614,46 @@ func (r *OrganizationConfigResource) read(ctx context.Context, orgModel map[stri
 	m.to("dayEpoch", &resourceModel.DayEpoch)
 	m.to("currency", &resourceModel.Currency)
 	m.to("daysBeforeBillDue", &resourceModel.DaysBeforeBillDue)
-	m.to("scheduledBillInterval", &resourceModel.ScheduledBillInterval)
-	m.to("standingChargeBillInAdvance", &resourceModel.StandingChargeBillInAdvance)
-	m.to("commitmentFeeBillInAdvance", &resourceModel.CommitmentFeeBillInAdvance)
-	m.to("minimumSpendBillInAdvance", &resourceModel.MinimumSpendBillInAdvance)
+	// The interval is always refreshed from the server, so UseStateForUnknown
+	// only keeps plans quiet and never hides an out-of-band change. The API
+	// omits it when scheduled bill updates are disabled, which is equivalent
+	// to 0, and a value matching the prior one as a decimal keeps the prior
+	// representation.
+	if interval, ok := orgModel["scheduledBillInterval"].(float64); ok {
+		prior := resourceModel.ScheduledBillInterval
+		if prior.IsUnknown() || prior.IsNull() || !decimalEqual(prior.ValueFloat64(), interval) {
+			resourceModel.ScheduledBillInterval = types.Float64Value(interval)
+		}
+	} else {
+		resourceModel.ScheduledBillInterval = types.Float64Value(0)
+	}
 	m.to("externalInvoiceDate", &resourceModel.ExternalInvoiceDate)
-	m.to("suppressedEmptyBills", &resourceModel.SuppressedEmptyBills)
-	m.to("consolidateBills", &resourceModel.ConsolidateBills)
+	// The API may omit flags which are false, so an omitted flag reads as
+	// false rather than keeping the prior value, which would hide a flag
+	// toggled off
+	flags := map[string]*types.Bool{
+		"standingChargeBillInAdvance": &resourceModel.StandingChargeBillInAdvance,
+		"commitmentFeeBillInAdvance":  &resourceModel.CommitmentFeeBillInAdvance,
+		"minimumSpendBillInAdvance":   &resourceModel.MinimumSpendBillInAdvance,
+		"suppressedEmptyBills":        &resourceModel.SuppressedEmptyBills,
+		"consolidateBills":            &resourceModel.ConsolidateBills,
+	}
+	for key, flag := range flags {
+		markMapped(ctx, key)
+		value, _ := orgModel[key].(bool)
+		*flag = types.BoolValue(value)
+	}
 	m.to("defaultStatementDefinitionId", &resourceModel.DefaultStatementDefinitionId)
 	m.to("sequenceStartNumber", &resourceModel.SequenceStartNumber)
+	m.to("billPrefix", &resourceModel.BillPrefix)
+	if resourceModel.BillPrefix.IsUnknown() {
+		resourceModel.BillPrefix = types.StringNull()
+	}
 	m.to("autoGenerateStatementMode", &resourceModel.AutoGenerateStatementMode)
-	m.listTo("currencyConversions", &resourceModel.CurrencyConversions, currencyConversionType.Type(), func(v any) (attr.Value, diag.Diagnostics) {
+	// The conversions are a set, so the order they're returned in doesn't
+	// matter
+	priorMultipliers := currencyConversionMultipliers(resourceModel.CurrencyConversions)
+	m.setTo("currencyConversions", &resourceModel.CurrencyConversions, currencyConversionType.Type(), func(v any) (attr.Value, diag.Diagnostics) {
 		mv, ok := v.(map[string]any)
 
 		if !ok {
@@ -544,6 +673,12 @@ func (r *OrganizationConfigResource) read(ctx context.Context, orgModel map[stri
 		m.to("to", &to)
 		m.to("multiplier", &multiplier)
 
+		// Keep the prior multiplier if the API only returned a different
+		// binary representation of the same decimal value
+		if prior, ok := priorMultipliers[[2]string{from.ValueString(), to.ValueString()}]; ok && decimalEqual(prior.ValueFloat64(), multiplier.ValueFloat64()) {
+			multiplier = prior
+		}
+
 		return types.ObjectValue(map[string]attr.Type{
 			"from":       types.StringType,
 			"to":         types.StringType,
@@ -564,3 +699,40 @@ func (r *OrganizationConfigResource) read(ctx context.Context, orgModel map[stri
 		return types.StringValue(mv), nil
 	})
 }
+
+// currencyConversionMultipliers returns the known multipliers of the given
+// conversions, keyed by their (from, to) currency pair.
+func currencyConversionMultipliers(conversions types.Set) map[[2]string]types.Float64 {
+	multipliers := make(map[[2]string]types.Float64)
+	if conversions.IsUnknown() || conversions.IsNull() {
+		return multipliers
+	}
+
+	for _, e := range conversions.Elements() {
+		if ov, ok := e.(types.Object); ok {
+			from, _ := ov.Attributes()["from"].(types.String)
+			to, _ := ov.Attributes()["to"].(types.String)
+			multiplier, ok := ov.Attributes()["multiplier"].(types.Float64)
+			if ok && !multiplier.IsUnknown() && !multiplier.IsNull() {
+				multipliers[[2]string{from.ValueString(), to.ValueString()}] = multiplier
+			}
+		}
+	}
+	return multipliers
+}
+
+// decimalTolerance is the relative difference under which two floats are
+// taken to be the same decimal value. The API returns decimals converted to
+// floats again, which may differ from the floats sent in their last bits
+// only, far below this tolerance, while the multipliers and intervals set in
+// configurations differ by far more.
+const decimalTolerance = 1e-12
+
+// decimalEqual reports whether a and b represent the same decimal value, so
+// that for example 1.1 and 1.1000000000000001 compare equal.
+func decimalEqual(a, b float64) bool {
+	if a == b {
+		return true
+	}
+	return math.Abs(a-b) <= decimalTolerance*math.Max(math.Abs(a), math.Abs(b))
+}
@@ -0,0 +1,255 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"maps"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func testOrganizationConfig() map[string]any {
+	return map[string]any{
+		"timezone":    "UTC",
+		"year_epoch":  "2024-01-01",
+		"month_epoch": "2024-01-01",
+		"week_epoch":  "2024-01-01",
+		"day_epoch":   "2024-01-01",
+		"currency":    "USD",
+	}
+}
+
+func TestOrganizationConfigResourceImportCurrencyConversions(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	api.put("organizationconfig", map[string]any{
+		"version":    float64(3),
+		"timezone":   "UTC",
+		"yearEpoch":  "2024-01-01",
+		"monthEpoch": "2024-01-01",
+		"weekEpoch":  "2024-01-01",
+		"dayEpoch":   "2024-01-01",
+		"currency":   "USD",
+		"currencyConversions": []any{
+			map[string]any{"from": "GBP", "to": "USD", "multiplier": 1.27},
+			map[string]any{"from": "EUR", "to": "USD", "multiplier": 1.1},
+		},
+	})
+
+	state := p.importState("m3ter_organization_config", testOrganizationID)
+
+	// The conversions are configured in another order than the API returns
+	config := testOrganizationConfig()
+	config["currency_conversions"] = []any{
+		map[string]any{"from": "EUR", "to": "USD", "multiplier": 1.1},
+		map[string]any{"from": "GBP", "to": "USD", "multiplier": 1.27},
+	}
+	p.assertNoChanges("m3ter_organization_config", state, config)
+}
+
+func TestOrganizationConfigResourceNoContent(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	config := testOrganizationConfig()
+	state := p.create("m3ter_organization_config", config)
+
+	// An empty config keeps the prior state rather than failing to decode
+	api.handle(func(w http.ResponseWriter, req *fakeRequest) bool {
+		if req.Method != http.MethodGet || req.Path != "/organizationconfig" {
+			return false
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return true
+	})
+	refreshed := p.read("m3ter_organization_config", state)
+	if v := attrValue(t, refreshed, "currency"); v != "USD" {
+		t.Errorf("currency = %v, want the prior USD", v)
+	}
+	p.assertNoChanges("m3ter_organization_config", refreshed, config)
+
+	data := p.readDataSource("m3ter_organization_config", map[string]any{})
+	if v := attrValue(t, data, "id"); v != testOrganizationID {
+		t.Errorf("id = %v, want %s", v, testOrganizationID)
+	}
+}
+
+func TestDecimalEqual(t *testing.T) {
+	tests := []struct {
+		a, b float64
+		want bool
+	}{
+		{1.1, 1.1, true},
+		{1.1, 1.1000000000000001, true},
+		{0.333333, math.Nextafter(0.333333, 1), true},
+		{0.333333, 0.333334, false},
+		{1.1, 1.11, false},
+		{0, 0, true},
+		{0, 1e-300, false},
+		{1e12, 1e12 + 0.0001, true},
+		{1e12, 1e12 + 10, false},
+	}
+	for _, tt := range tests {
+		if got := decimalEqual(tt.a, tt.b); got != tt.want {
+			t.Errorf("decimalEqual(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestOrganizationConfigResourceMultipliers(t *testing.T) {
+	api := newFakeAPI(t)
+	// The API returns the multipliers it stores as slightly different floats
+	api.onWrite = func(collection string, entity map[string]any) {
+		conversions, _ := entity["currencyConversions"].([]any)
+		for _, conversion := range conversions {
+			conversion := conversion.(map[string]any)
+			conversion["multiplier"] = math.Nextafter(conversion["multiplier"].(float64), 2)
+		}
+	}
+	p := newTestProvider(t, api, nil)
+
+	config := testOrganizationConfig()
+	config["currency_conversions"] = []any{
+		map[string]any{"from": "EUR", "to": "USD", "multiplier": 1.1},
+		map[string]any{"from": "GBP", "to": "USD", "multiplier": 0.333333},
+	}
+	state := p.create("m3ter_organization_config", config)
+	state = p.read("m3ter_organization_config", state)
+	p.assertNoChanges("m3ter_organization_config", state, config)
+
+	// A changed multiplier is still planned
+	config["currency_conversions"] = []any{
+		map[string]any{"from": "EUR", "to": "USD", "multiplier": 1.1},
+		map[string]any{"from": "GBP", "to": "USD", "multiplier": 0.333334},
+	}
+	planned, diags := p.plan("m3ter_organization_config", state, config)
+	p.checkDiagnostics("plan", diags)
+	if planned.Equal(state) {
+		t.Errorf("changing a multiplier planned no changes")
+	}
+}
+
+func TestOrganizationConfigResourceScheduledBillInterval(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	config := testOrganizationConfig()
+	config["scheduled_bill_interval"] = 0.5
+	state := p.create("m3ter_organization_config", config)
+	state = p.read("m3ter_organization_config", state)
+	p.assertNoChanges("m3ter_organization_config", state, config)
+
+	// Leaving the interval out of the config keeps the one on the server
+	unset := testOrganizationConfig()
+	p.assertNoChanges("m3ter_organization_config", state, unset)
+
+	// An out-of-band change is detected on refresh and planned back
+	changed := maps.Clone(api.get("organizationconfig", ""))
+	changed["scheduledBillInterval"] = float64(1)
+	api.put("organizationconfig", changed)
+	state = p.read("m3ter_organization_config", state)
+	if v := attrValue(t, state, "scheduled_bill_interval"); v != float64(1) {
+		t.Errorf("refreshed scheduled_bill_interval = %v, want 1", v)
+	}
+	planned, diags := p.plan("m3ter_organization_config", state, config)
+	p.checkDiagnostics("plan", diags)
+	if v := attrValue(t, planned, "scheduled_bill_interval"); v != 0.5 {
+		t.Errorf("planned scheduled_bill_interval = %v, want 0.5", v)
+	}
+
+	// The API omits the interval once scheduled updates are disabled
+	delete(changed, "scheduledBillInterval")
+	api.put("organizationconfig", changed)
+	state = p.read("m3ter_organization_config", state)
+	if v := attrValue(t, state, "scheduled_bill_interval"); v != float64(0) {
+		t.Errorf("refreshed scheduled_bill_interval = %v, want 0", v)
+	}
+}
+
+func TestOrganizationConfigResourceConsolidateSuppressed(t *testing.T) {
+	api := newFakeAPI(t)
+	// The API omits the flags which are false
+	api.onWrite = func(collection string, entity map[string]any) {
+		for _, key := range []string{"consolidateBills", "suppressedEmptyBills"} {
+			if entity[key] == false {
+				delete(entity, key)
+			}
+		}
+	}
+	p := newTestProvider(t, api, nil)
+
+	config := testOrganizationConfig()
+	config["consolidate_bills"] = true
+	config["suppressed_empty_bills"] = true
+	_, diags := p.plan("m3ter_organization_config", p.null("m3ter_organization_config"), config)
+	p.checkDiagnostics("plan", diags)
+	if summary := diagnosticsSummary(diags); !strings.Contains(summary, "Empty Bills suppressed with consolidation") {
+		t.Errorf("got diagnostics %q, want a warning about suppression with consolidation", summary)
+	}
+	state := p.create("m3ter_organization_config", config)
+
+	// Both flags are sent as false when toggled off, and read back as false
+	config["consolidate_bills"] = false
+	config["suppressed_empty_bills"] = false
+	api.clearRequests()
+	state = p.update("m3ter_organization_config", state, config)
+	sent := api.requestsTo("PUT", "/organizationconfig")[0].Body
+	for _, key := range []string{"consolidateBills", "suppressedEmptyBills"} {
+		if v, ok := sent[key]; !ok || v != false {
+			t.Errorf("sent %s = %v, want false", key, v)
+		}
+	}
+	state = p.read("m3ter_organization_config", state)
+	for _, name := range []string{"consolidate_bills", "suppressed_empty_bills"} {
+		if v := attrValue(t, state, name); v != false {
+			t.Errorf("%s = %v, want false", name, v)
+		}
+	}
+	p.assertNoChanges("m3ter_organization_config", state, config)
+}
+
+func TestOrganizationConfigResourceFalseFlags(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	flags := map[string]string{
+		"standing_charge_bill_in_advance": "standingChargeBillInAdvance",
+		"commitment_fee_bill_in_advance":  "commitmentFeeBillInAdvance",
+		"minimum_spend_bill_in_advance":   "minimumSpendBillInAdvance",
+		"suppressed_empty_bills":          "suppressedEmptyBills",
+		"consolidate_bills":               "consolidateBills",
+	}
+
+	config := testOrganizationConfig()
+	for name := range flags {
+		config[name] = true
+	}
+	state := p.create("m3ter_organization_config", config)
+
+	// Toggling every flag off sends false, which then persists
+	for name := range flags {
+		config[name] = false
+	}
+	api.clearRequests()
+	state = p.update("m3ter_organization_config", state, config)
+	sent := api.requestsTo("PUT", "/organizationconfig")[0].Body
+	for name, key := range flags {
+		if v, ok := sent[key]; !ok || v != false {
+			t.Errorf("sent %s = %v, want false", key, v)
+		}
+		if v := attrValue(t, state, name); v != false {
+			t.Errorf("%s = %v after update, want false", name, v)
+		}
+	}
+	state = p.read("m3ter_organization_config", state)
+	for name := range flags {
+		if v := attrValue(t, state, name); v != false {
+			t.Errorf("%s = %v after refresh, want false", name, v)
+		}
+	}
+	p.assertNoChanges("m3ter_organization_config", state, config)
+}
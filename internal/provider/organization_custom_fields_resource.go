@@ -0,0 +1,283 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &OrganizationCustomFieldsResource{}
+var _ resource.ResourceWithImportState = &OrganizationCustomFieldsResource{}
+
+func NewOrganizationCustomFieldsResource() resource.Resource {
+	return &OrganizationCustomFieldsResource{}
+}
+
+// OrganizationCustomFieldsResource defines the resource implementation.
+type OrganizationCustomFieldsResource struct {
+	client *m3terClient
+}
+
+// OrganizationCustomFieldsResourceModel describes the resource data model.
+type OrganizationCustomFieldsResourceModel struct {
+	CustomFields types.Dynamic `tfsdk:"custom_fields"`
+	Id           types.String  `tfsdk:"id"`
+	Version      types.Int64   `tfsdk:"version"`
+}
+
+func (r *OrganizationCustomFieldsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_organization_custom_fields"
+}
+
+func (r *OrganizationCustomFieldsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Organization custom fields resource, the custom fields defined for each type of entity and their default values. The definitions are managed as a whole: those of entity types missing from `custom_fields` are removed.",
+
+		Attributes: map[string]schema.Attribute{
+			"custom_fields": schema.DynamicAttribute{
+				MarkdownDescription: "The custom fields of each type of entity, keyed by the entity type as named by the API, such as `organization`, `product`, `plan`, `planTemplate`, `account`, `aggregation`, `compoundAggregation` or `meter`. The value for an entity type maps each custom field name to its default value, which can be a string, a number or a boolean.",
+				Required:            true,
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Organization identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"version": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Custom fields version",
+			},
+		},
+	}
+}
+
+func (r *OrganizationCustomFieldsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*m3terClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *m3terClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *OrganizationCustomFieldsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data OrganizationCustomFieldsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.put(ctx, &data, &resp.Diagnostics)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OrganizationCustomFieldsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data OrganizationCustomFieldsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The custom fields are a singleton which always exists
+	customFieldsData := make(map[string]any)
+	err := r.client.execute(ctx, "GET", "/customfields", nil, nil, &customFieldsData)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read custom fields, got error: %s", err))
+		return
+	}
+
+	r.read(ctx, customFieldsData, &data, &resp.Diagnostics)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OrganizationCustomFieldsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data OrganizationCustomFieldsResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.put(ctx, &data, &resp.Diagnostics)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *OrganizationCustomFieldsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// No need to do anything here - this just removes the custom fields from being managed by Terraform
+}
+
+func (r *OrganizationCustomFieldsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// put replaces the custom fields with the planned ones, then reads back the
+// result.
+func (r *OrganizationCustomFieldsResource) put(ctx context.Context, data *OrganizationCustomFieldsResourceModel, diagnostics *diag.Diagnostics) {
+	// The custom fields are a singleton which always exists, and is updated
+	// from its current version
+	customFieldsData := make(map[string]any)
+	err := r.client.execute(ctx, "GET", "/customfields", nil, nil, &customFieldsData)
+	if err != nil {
+		diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read custom fields, got error: %s", err))
+		return
+	}
+
+	r.write(ctx, data, customFieldsData, diagnostics)
+	if diagnostics.HasError() {
+		return
+	}
+
+	var updatedCustomFieldsData map[string]any
+	err = r.client.execute(ctx, "PUT", "/customfields", nil, customFieldsData, &updatedCustomFieldsData)
+	if err != nil {
+		diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update custom fields, got error: %s", err))
+		return
+	}
+
+	r.read(ctx, updatedCustomFieldsData, data, diagnostics)
+}
+
+// entityCustomFields returns the custom fields of each entity type in a
+// custom_fields value.
+func entityCustomFields(customFields types.Dynamic) map[string]attr.Value {
+	if customFields.IsNull() || customFields.IsUnknown() || customFields.IsUnderlyingValueNull() || customFields.IsUnderlyingValueUnknown() {
+		return nil
+	}
+
+	switch v := customFields.UnderlyingValue().(type) {
+	case types.Object:
+		return v.Attributes()
+	case types.Map:
+		return v.Elements()
+	default:
+		return nil
+	}
+}
+
+func (r *OrganizationCustomFieldsResource) read(ctx context.Context, customFieldsData map[string]any, data *OrganizationCustomFieldsResourceModel, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           customFieldsData,
+	}
+	data.Id = types.StringValue(r.client.organizationID)
+	m.to("version", &data.Version)
+
+	prior := entityCustomFields(data.CustomFields)
+	typ := make(map[string]attr.Type)
+	values := make(map[string]attr.Value)
+	for entity, v := range customFieldsData {
+		// Every object in the document holds the custom fields of an
+		// entity type
+		fields, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		// Entity types without custom fields are only read when configured
+		priorFields, configured := prior[entity]
+		if !configured && len(fields) == 0 {
+			continue
+		}
+
+		// Keep the configured shape of the custom fields
+		target := types.DynamicValue(types.ObjectNull(nil))
+		if configured {
+			if dv, ok := priorFields.(types.Dynamic); ok {
+				target = dv
+			} else {
+				target = types.DynamicValue(priorFields)
+			}
+		}
+		entityM := &mapper{
+			ctx:         ctx,
+			diagnostics: diagnostics,
+			v:           map[string]any{"customFields": fields},
+		}
+		entityM.customFieldsTo(&target)
+		values[entity] = target.UnderlyingValue()
+		typ[entity] = values[entity].Type(ctx)
+	}
+
+	ov, diag := types.ObjectValue(typ, values)
+	diagnostics.Append(diag...)
+	data.CustomFields = types.DynamicValue(ov)
+}
+
+func (r *OrganizationCustomFieldsResource) write(ctx context.Context, data *OrganizationCustomFieldsResourceModel, customFieldsData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           customFieldsData,
+	}
+	m.from(data.Version, "version")
+
+	if data.CustomFields.IsUnknown() || data.CustomFields.IsUnderlyingValueUnknown() {
+		return
+	}
+
+	planned := entityCustomFields(data.CustomFields)
+
+	// The definitions are managed as a whole, so those of entity types which
+	// aren't configured are removed
+	for entity, v := range customFieldsData {
+		if _, ok := v.(map[string]any); ok {
+			if _, ok := planned[entity]; !ok {
+				customFieldsData[entity] = map[string]any{}
+			}
+		}
+	}
+
+	for entity, v := range planned {
+		dv, ok := v.(types.Dynamic)
+		if !ok {
+			dv = types.DynamicValue(v)
+		}
+		entityM := &mapper{
+			ctx:         ctx,
+			diagnostics: diagnostics,
+			v:           make(map[string]any),
+		}
+		entityM.customFieldsFrom(dv)
+		customFieldsData[entity] = entityM.v["customFields"]
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &picklistResource[PicklistResourceModel, *PicklistResourceModel]{}
+var _ resource.ResourceWithImportState = &picklistResource[PicklistResourceModel, *PicklistResourceModel]{}
+
+// newPicklistResource returns a resource managing the entries of the picklist
+// at path, which all have a name, a code and an archived flag. name is the
+// human readable name of an entry used in descriptions and diagnostics.
+// Picklists whose entries have more fields pass their attributes, a model
+// embedding PicklistResourceModel, and the functions mapping the fields.
+func newPicklistResource[T any, PT picklistModel[T]](typeName, path, name, description string, attributes map[string]schema.Attribute, read, write func(*mapper, PT)) resource.Resource {
+	r := &picklistResource[T, PT]{
+		description: description,
+		attributes:  attributes,
+		readFields:  read,
+		writeFields: write,
+	}
+	r.genericResource = genericResource[T, PT]{
+		typeName:     typeName,
+		path:         path,
+		name:         name,
+		importFields: []string{"code"},
+		read:         r.read,
+		write:        r.write,
+	}
+	return r
+}
+
+func NewCreditReasonResource() resource.Resource {
+	return newPicklistResource[PicklistResourceModel]("credit_reason", "/picklists/creditreasons", "credit reason", "Credit reason resource, an entry of the picklist of reasons Credits are applied to Bills for.", nil, nil, nil)
+}
+
+// picklistModel is the model of a picklist resource, either
+// PicklistResourceModel or a struct embedding it.
+type picklistModel[T any] interface {
+	idable[T]
+
+	picklist() *PicklistResourceModel
+}
+
+// picklistResource defines the resource implementation.
+type picklistResource[T any, PT picklistModel[T]] struct {
+	genericResource[T, PT]
+
+	// description is the markdown description of the resource
+	description string
+	// attributes are the attributes of the entry fields besides the common ones
+	attributes map[string]schema.Attribute
+	// readFields and writeFields, when set, map the fields of attributes
+	readFields  func(*mapper, PT)
+	writeFields func(*mapper, PT)
+}
+
+// PicklistResourceModel describes the resource data model.
+type PicklistResourceModel struct {
+	Name     types.String   `tfsdk:"name"`
+	Code     types.String   `tfsdk:"code"`
+	Archived types.Bool     `tfsdk:"archived"`
+	Id       types.String   `tfsdk:"id"`
+	Version  types.Int64    `tfsdk:"version"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *PicklistResourceModel) GetId() types.String {
+	return r.Id
+}
+
+func (r *PicklistResourceModel) picklist() *PicklistResourceModel {
+	return r
+}
+
+func (r *picklistResource[T, PT]) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	attributes := map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			MarkdownDescription: fmt.Sprintf("Descriptive name of the %s.", r.name),
+			Required:            true,
+			Validators: []validator.String{
+				stringvalidator.LengthBetween(1, 200),
+			},
+		},
+		"code": schema.StringAttribute{
+			MarkdownDescription: fmt.Sprintf("A unique short code to identify the %s.", r.name),
+			Optional:            true,
+			Computed:            true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+			Validators: []validator.String{
+				stringvalidator.LengthBetween(1, 80),
+				stringvalidator.RegexMatches(regexp.MustCompile(`^([^\p{Cc}\s])|([^\p{Cc}\s][[^\p{Cc}\s] ]*[^\p{Cc}\s])$`), "The code must not contain control characters or start/end with whitespace."),
+			},
+		},
+		"archived": schema.BoolAttribute{
+			MarkdownDescription: fmt.Sprintf("Whether the %s is archived. Archived entries can no longer be selected for new entities.", r.name),
+			Optional:            true,
+			Computed:            true,
+			Default:             booldefault.StaticBool(false),
+		},
+		"id": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "The UUID of the entity.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"version": schema.Int64Attribute{
+			Computed:            true,
+			MarkdownDescription: "The version number.",
+		},
+	}
+	maps.Copy(attributes, r.attributes)
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: r.description,
+
+		Attributes: attributes,
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
+	}
+}
+
+func (r *picklistResource[T, PT]) read(ctx context.Context, model *T, restData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           restData,
+	}
+	data := PT(model).picklist()
+	m.to("id", &data.Id)
+	m.to("version", &data.Version)
+	m.to("name", &data.Name)
+	m.to("code", &data.Code)
+	if data.Code.IsUnknown() {
+		data.Code = types.StringNull()
+	}
+	markMapped(ctx, "archived")
+	archived, _ := restData["archived"].(bool)
+	data.Archived = types.BoolValue(archived)
+	if r.readFields != nil {
+		r.readFields(m, model)
+	}
+}
+
+func (r *picklistResource[T, PT]) write(ctx context.Context, model *T, restData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           restData,
+	}
+	data := PT(model).picklist()
+	m.from(data.Id, "id")
+	m.from(data.Version, "version")
+	m.from(data.Name, "name")
+	m.from(data.Code, "code")
+	m.from(data.Archived, "archived")
+	if r.writeFields != nil {
+		r.writeFields(m, model)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &PlanDataSource{}
+
+func NewPlanDataSource() datasource.DataSource {
+	r := &PlanDataSource{}
+	r.genericDataSource = genericDataSource[PlanDataSourceModel, *PlanDataSourceModel]{
+		typeName: "plan",
+		path:     "/plans",
+		name:     "plan",
+		filters: func(data *PlanDataSourceModel) map[string]types.String {
+			return map[string]types.String{
+				"name": data.Name,
+				"code": data.Code,
+			}
+		},
+		extraQuery: func(data *PlanDataSourceModel) types.Map {
+			return data.ExtraQuery
+		},
+		read: r.read,
+	}
+	return r
+}
+
+// PlanDataSource defines the data source implementation.
+type PlanDataSource struct {
+	genericDataSource[PlanDataSourceModel, *PlanDataSourceModel]
+}
+
+type PlanDataSourceModel struct {
+	Name           types.String  `tfsdk:"name"`
+	Code           types.String  `tfsdk:"code"`
+	PlanTemplateId types.String  `tfsdk:"plan_template_id"`
+	StandingCharge types.Float64 `tfsdk:"standing_charge"`
+	CustomFields   types.Dynamic `tfsdk:"custom_fields"`
+	Id             types.String  `tfsdk:"id"`
+	Version        types.Int64   `tfsdk:"version"`
+	ExtraQuery     types.Map     `tfsdk:"extra_query"`
+}
+
+func (r *PlanDataSourceModel) GetId() types.String {
+	return r.Id
+}
+
+func (r *PlanDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Plan data source",
+
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Descriptive name for the Plan.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"code": schema.StringAttribute{
+				MarkdownDescription: "Unique short code reference for the Plan.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"plan_template_id": schema.StringAttribute{
+				MarkdownDescription: "UUID of the PlanTemplate the Plan belongs to.",
+				Computed:            true,
+			},
+			"standing_charge": schema.Float64Attribute{
+				MarkdownDescription: "The standing charge applied to bills for end customers, if the Plan overrides the one of its PlanTemplate.",
+				Computed:            true,
+			},
+			"custom_fields": schema.DynamicAttribute{
+				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be a string, a number or a boolean.",
+				Computed:            true,
+			},
+			"id": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The UUID of the entity.",
+			},
+			"version": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The version number.",
+			},
+			"extra_query": extraQueryAttribute,
+		},
+	}
+}
+
+func (r *PlanDataSource) read(ctx context.Context, data *PlanDataSourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           restData,
+	}
+	m.to("id", &data.Id)
+	m.to("version", &data.Version)
+	m.to("name", &data.Name)
+	m.to("code", &data.Code)
+	m.to("planTemplateId", &data.PlanTemplateId)
+	m.to("standingCharge", &data.StandingCharge)
+	m.customFieldsTo(&data.CustomFields)
+}
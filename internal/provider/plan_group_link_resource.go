@@ -6,7 +6,9 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/url"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -19,6 +21,7 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &PlanGroupLinkResource{}
 var _ resource.ResourceWithImportState = &PlanGroupLinkResource{}
+var _ resource.ResourceWithModifyPlan = &PlanGroupLinkResource{}
 
 func NewPlanGroupLinkResource() resource.Resource {
 	return &PlanGroupLinkResource{}
@@ -31,10 +34,11 @@ type PlanGroupLinkResource struct {
 
 // PlanGroupLinkResourceModel describes the resource data model.
 type PlanGroupLinkResourceModel struct {
-	PlanGroupId types.String `tfsdk:"plan_group_id"`
-	PlanId      types.String `tfsdk:"plan_id"`
-	Id          types.String `tfsdk:"id"`
-	Version     types.Int64  `tfsdk:"version"`
+	PlanGroupId types.String   `tfsdk:"plan_group_id"`
+	PlanId      types.String   `tfsdk:"plan_id"`
+	Id          types.String   `tfsdk:"id"`
+	Version     types.Int64    `tfsdk:"version"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *PlanGroupLinkResourceModel) GetId() types.String {
@@ -76,6 +80,9 @@ func (r *PlanGroupLinkResource) Schema(ctx context.Context, req resource.SchemaR
 				MarkdownDescription: "The version number of the entity.",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -119,6 +126,75 @@ func (r *PlanGroupLinkResource) ImportState(ctx context.Context, req resource.Im
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+func (r *PlanGroupLinkResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy, or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var data PlanGroupLinkResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.PlanGroupId.IsUnknown() || data.PlanId.IsUnknown() {
+		return
+	}
+
+	// The currencies were already checked when the link was planned, so
+	// there's no need to look them up again unless it's replaced
+	if !req.State.Raw.IsNull() {
+		var state PlanGroupLinkResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if state.PlanGroupId.Equal(data.PlanGroupId) && state.PlanId.Equal(data.PlanId) {
+			return
+		}
+	}
+
+	checkPlanGroupCurrency(ctx, r.client, data.PlanGroupId.ValueString(), []string{data.PlanId.ValueString()}, &resp.Diagnostics)
+}
+
+// checkPlanGroupCurrency warns about each of the given plans whose currency,
+// which comes from its plan template, differs from the plan group's currency.
+// This is a best-effort check, so any failure to look up the entities is ignored.
+func checkPlanGroupCurrency(ctx context.Context, client *m3terClient, planGroupId string, planIds []string, diagnostics *diag.Diagnostics) {
+	var planGroup map[string]any
+	if err := client.execute(ctx, "GET", "/plangroups/"+url.PathEscape(planGroupId), nil, nil, &planGroup); err != nil {
+		return
+	}
+	planGroupCurrency, _ := planGroup["currency"].(string)
+	if planGroupCurrency == "" {
+		return
+	}
+
+	for _, planId := range planIds {
+		var plan map[string]any
+		if err := client.execute(ctx, "GET", "/plans/"+url.PathEscape(planId), nil, nil, &plan); err != nil {
+			continue
+		}
+		planTemplateId, ok := plan["planTemplateId"].(string)
+		if !ok {
+			continue
+		}
+		var planTemplate map[string]any
+		if err := client.execute(ctx, "GET", "/plantemplates/"+url.PathEscape(planTemplateId), nil, nil, &planTemplate); err != nil {
+			continue
+		}
+
+		planCurrency, _ := planTemplate["currency"].(string)
+		if planCurrency != "" && planCurrency != planGroupCurrency {
+			diagnostics.AddWarning(
+				"Plan currency does not match plan group currency",
+				fmt.Sprintf("Plan %s uses currency %s (from its plan template), but plan group %s uses currency %s.", planId, planCurrency, planGroupId, planGroupCurrency),
+			)
+		}
+	}
+}
+
 func (r *PlanGroupLinkResource) read(ctx context.Context, data *PlanGroupLinkResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
 	m := &mapper{
 		ctx:         ctx,
@@ -0,0 +1,37 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPlanGroupLinkResourceCurrencyCheck(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	planGroupId := api.put("plangroups", map[string]any{"currency": "USD"})
+	planTemplateId := api.put("plantemplates", map[string]any{"currency": "GBP"})
+	planId := api.put("plans", map[string]any{"planTemplateId": planTemplateId})
+	config := map[string]any{"plan_group_id": planGroupId, "plan_id": planId}
+
+	planned, diags := p.plan("m3ter_plan_group_link", p.null("m3ter_plan_group_link"), config)
+	if summary := diagnosticsSummary(diags); !strings.Contains(summary, "Plan currency does not match plan group currency") {
+		t.Errorf("got diagnostics %q, want a currency mismatch warning", summary)
+	}
+	state, diags := p.apply("m3ter_plan_group_link", p.null("m3ter_plan_group_link"), planned, config)
+	p.checkDiagnostics("create", diags)
+
+	// Planning the unchanged link doesn't look up the entities again
+	api.clearRequests()
+	_, diags = p.plan("m3ter_plan_group_link", state, config)
+	if len(diags) != 0 {
+		t.Errorf("got diagnostics %q, want none", diagnosticsSummary(diags))
+	}
+	if len(api.requestsTo(http.MethodGet, "/")) != 0 {
+		t.Errorf("sent %v, want no requests", api.sentRequests())
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &PlanGroupLinksDataSource{}
+var _ datasource.DataSourceWithConfigure = &PlanGroupLinksDataSource{}
+
+func NewPlanGroupLinksDataSource() datasource.DataSource {
+	r := &PlanGroupLinksDataSource{}
+	r.genericListDataSource = genericListDataSource[PlanGroupLinksDataSourceModel, planGroupLinksEntryModel]{
+		typeName:        "plan_group_links",
+		path:            "/plangrouplinks",
+		name:            "plan group link",
+		orderBy:         "planId",
+		entryAttributes: planGroupLinksEntryAttributes,
+		query: func(ctx context.Context, data *PlanGroupLinksDataSourceModel, diagnostics *diag.Diagnostics) url.Values {
+			query := url.Values{}
+			query.Set("planGroup", data.PlanGroupId.ValueString())
+			return query
+		},
+		match: func(data *PlanGroupLinksDataSourceModel, restData map[string]any) bool {
+			id, ok := restData["planGroupId"].(string)
+			return ok && id == data.PlanGroupId.ValueString()
+		},
+		extraQuery: func(data *PlanGroupLinksDataSourceModel) types.Map {
+			return data.ExtraQuery
+		},
+		entries: func(data *PlanGroupLinksDataSourceModel) *types.List {
+			return &data.Links
+		},
+		read: r.read,
+	}
+	return r
+}
+
+// PlanGroupLinksDataSource defines the data source implementation.
+type PlanGroupLinksDataSource struct {
+	genericListDataSource[PlanGroupLinksDataSourceModel, planGroupLinksEntryModel]
+}
+
+type PlanGroupLinksDataSourceModel struct {
+	PlanGroupId types.String `tfsdk:"plan_group_id"`
+	Links       types.List   `tfsdk:"links"`
+	ExtraQuery  types.Map    `tfsdk:"extra_query"`
+}
+
+type planGroupLinksEntryModel struct {
+	PlanId  types.String `tfsdk:"plan_id"`
+	Id      types.String `tfsdk:"id"`
+	Version types.Int64  `tfsdk:"version"`
+}
+
+var planGroupLinksEntryAttributes = map[string]schema.Attribute{
+	"plan_id": schema.StringAttribute{
+		MarkdownDescription: "Identifier of the plan linked to the plan group",
+		Computed:            true,
+	},
+	"id": schema.StringAttribute{
+		MarkdownDescription: "Plan group link identifier",
+		Computed:            true,
+	},
+	"version": schema.Int64Attribute{
+		MarkdownDescription: "Plan group link version",
+		Computed:            true,
+	},
+}
+
+func (r *PlanGroupLinksDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "PlanGroupLinks data source. Lists the links of a plan group.",
+
+		Attributes: map[string]schema.Attribute{
+			"plan_group_id": schema.StringAttribute{
+				MarkdownDescription: "Plan group identifier",
+				Required:            true,
+			},
+			"links": schema.ListNestedAttribute{
+				MarkdownDescription: "The links of the plan group, ordered by plan identifier.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: planGroupLinksEntryAttributes,
+				},
+			},
+			"extra_query": extraQueryAttribute,
+		},
+	}
+}
+
+func (r *PlanGroupLinksDataSource) read(ctx context.Context, entry *planGroupLinksEntryModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           restData,
+	}
+	m.to("planId", &entry.PlanId)
+	m.to("id", &entry.Id)
+	m.to("version", &entry.Version)
+}
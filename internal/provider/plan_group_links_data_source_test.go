@@ -0,0 +1,34 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPlanGroupLinksDataSource(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+	planGroupId := "00000000-0000-4000-8000-0000000000a1"
+	api.put("plangrouplinks", map[string]any{"planGroupId": planGroupId, "planId": "00000000-0000-4000-8000-0000000000b2"})
+	api.put("plangrouplinks", map[string]any{"planGroupId": "00000000-0000-4000-8000-0000000000a2", "planId": "00000000-0000-4000-8000-0000000000b3"})
+	api.put("plangrouplinks", map[string]any{"planGroupId": planGroupId, "planId": "00000000-0000-4000-8000-0000000000b1"})
+
+	// The API is sent a planGroup filter which the fake ignores, so the links
+	// of other plan groups are left out by the data source
+	state := p.readDataSource("m3ter_plan_group_links", map[string]any{"plan_group_id": planGroupId})
+	if v := attrValue(t, state, "links"); v != 2 {
+		t.Fatalf("listed %v links, want 2", v)
+	}
+	for i, planId := range []string{"00000000-0000-4000-8000-0000000000b1", "00000000-0000-4000-8000-0000000000b2"} {
+		if v := attrValue(t, state, fmt.Sprintf("links.%d.plan_id", i)); v != planId {
+			t.Errorf("link %d has plan_id %v, want %s", i, v, planId)
+		}
+	}
+	lists := api.requestsTo("GET", "/plangrouplinks")
+	if len(lists) != 1 || lists[0].Query.Get("planGroup") != planGroupId {
+		t.Errorf("the links were not listed filtered by plan group")
+	}
+}
@@ -0,0 +1,293 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &PlanGroupLinksResource{}
+var _ resource.ResourceWithImportState = &PlanGroupLinksResource{}
+
+func NewPlanGroupLinksResource() resource.Resource {
+	return &PlanGroupLinksResource{}
+}
+
+// PlanGroupLinksResource defines the resource implementation.
+type PlanGroupLinksResource struct {
+	client *m3terClient
+}
+
+// PlanGroupLinksResourceModel describes the resource data model.
+type PlanGroupLinksResourceModel struct {
+	PlanGroupId types.String   `tfsdk:"plan_group_id"`
+	PlanIds     types.Set      `tfsdk:"plan_ids"`
+	Id          types.String   `tfsdk:"id"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *PlanGroupLinksResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_plan_group_links"
+}
+
+func (r *PlanGroupLinksResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "PlanGroupLinks resource. Manages all of the links of a plan group as a single resource, so it should not be combined with `m3ter_plan_group_link` resources for the same plan group.",
+
+		Attributes: map[string]schema.Attribute{
+			"plan_group_id": schema.StringAttribute{
+				MarkdownDescription: "Plan group identifier",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"plan_ids": schema.SetAttribute{
+				MarkdownDescription: "Identifiers of the plans linked to the plan group",
+				ElementType:         types.StringType,
+				Required:            true,
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The UUID of the plan group.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
+	}
+}
+
+func (r *PlanGroupLinksResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*m3terClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *m3terClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *PlanGroupLinksResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, cancel := withOperationTimeout(ctx, req.Plan, "create", &resp.Diagnostics)
+	defer cancel()
+	ctx, logCalls := countAPICalls(ctx, "create", "plan group links")
+	defer logCalls()
+
+	var data PlanGroupLinksResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.sync(ctx, &data, &resp.Diagnostics)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PlanGroupLinksResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, cancel := withOperationTimeout(ctx, req.State, "read", &resp.Diagnostics)
+	defer cancel()
+	ctx, logCalls := countAPICalls(ctx, "read", "plan group links")
+	defer logCalls()
+
+	var data PlanGroupLinksResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.read(ctx, &data, &resp.Diagnostics)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PlanGroupLinksResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, cancel := withOperationTimeout(ctx, req.Plan, "update", &resp.Diagnostics)
+	defer cancel()
+	ctx, logCalls := countAPICalls(ctx, "update", "plan group links")
+	defer logCalls()
+
+	var data PlanGroupLinksResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.sync(ctx, &data, &resp.Diagnostics)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PlanGroupLinksResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, cancel := withOperationTimeout(ctx, req.State, "delete", &resp.Diagnostics)
+	defer cancel()
+	ctx, logCalls := countAPICalls(ctx, "delete", "plan group links")
+	defer logCalls()
+
+	var data PlanGroupLinksResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	links, err := r.links(ctx, data.PlanGroupId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list plan group links, got error: %s", err))
+		return
+	}
+
+	for _, linkId := range links {
+		err := r.client.execute(ctx, "DELETE", "/plangrouplinks/"+url.PathEscape(linkId), nil, nil, nil)
+		if sc, ok := err.(*statusCodeError); ok && sc.StatusCode == 404 {
+			continue
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete plan group link, got error: %s", err))
+			return
+		}
+	}
+}
+
+func (r *PlanGroupLinksResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("plan_group_id"), req.ID)...)
+}
+
+// links returns the ids of the links of the given plan group, keyed by plan id.
+func (r *PlanGroupLinksResource) links(ctx context.Context, planGroupId string) (map[string]string, error) {
+	links := make(map[string]string)
+	query := url.Values{}
+	query.Set("planGroup", planGroupId)
+	err := r.client.list(ctx, "/plangrouplinks", query, func(restData map[string]any) bool {
+		if id, ok := restData["planGroupId"].(string); !ok || id != planGroupId {
+			return true
+		}
+		planId, _ := restData["planId"].(string)
+		linkId, _ := restData["id"].(string)
+		if planId != "" && linkId != "" {
+			links[planId] = linkId
+		}
+		return true
+	})
+	return links, err
+}
+
+// sync creates and deletes plan group links so that exactly the configured
+// plans are linked to the plan group, then reads back the result.
+func (r *PlanGroupLinksResource) sync(ctx context.Context, data *PlanGroupLinksResourceModel, diagnostics *diag.Diagnostics) {
+	planGroupId := data.PlanGroupId.ValueString()
+
+	var planIds []string
+	diagnostics.Append(data.PlanIds.ElementsAs(ctx, &planIds, false)...)
+	if diagnostics.HasError() {
+		return
+	}
+
+	links, err := r.links(ctx, planGroupId)
+	if err != nil {
+		diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list plan group links, got error: %s", err))
+		return
+	}
+
+	for planId, linkId := range links {
+		if slices.Contains(planIds, planId) {
+			continue
+		}
+		err := r.client.execute(ctx, "DELETE", "/plangrouplinks/"+url.PathEscape(linkId), nil, nil, nil)
+		if sc, ok := err.(*statusCodeError); ok && sc.StatusCode == 404 {
+			continue
+		}
+		if err != nil {
+			diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete plan group link, got error: %s", err))
+			return
+		}
+	}
+
+	var added []string
+	for _, planId := range planIds {
+		if _, ok := links[planId]; !ok {
+			added = append(added, planId)
+		}
+	}
+	if len(added) > 0 {
+		checkPlanGroupCurrency(ctx, r.client, planGroupId, added, diagnostics)
+	}
+
+	for _, planId := range added {
+		restData := map[string]any{
+			"planGroupId": planGroupId,
+			"planId":      planId,
+		}
+		err := r.client.execute(ctx, "POST", "/plangrouplinks", nil, restData, nil)
+		if err != nil {
+			diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create plan group link, got error: %s", err))
+			return
+		}
+	}
+
+	r.read(ctx, data, diagnostics)
+}
+
+func (r *PlanGroupLinksResource) read(ctx context.Context, data *PlanGroupLinksResourceModel, diagnostics *diag.Diagnostics) {
+	planGroupId := data.PlanGroupId.ValueString()
+
+	links, err := r.links(ctx, planGroupId)
+	if err != nil {
+		diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list plan group links, got error: %s", err))
+		return
+	}
+
+	planIds := make([]string, 0, len(links))
+	for planId := range links {
+		planIds = append(planIds, planId)
+	}
+	slices.Sort(planIds)
+
+	sv, diag := types.SetValueFrom(ctx, types.StringType, planIds)
+	diagnostics.Append(diag...)
+	data.PlanIds = sv
+	data.Id = types.StringValue(planGroupId)
+}
@@ -0,0 +1,43 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPlanGroupLinksResourceCurrencyCheck(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	planGroupId := api.put("plangroups", map[string]any{"currency": "USD"})
+	usdPlanId := api.put("plans", map[string]any{"planTemplateId": api.put("plantemplates", map[string]any{"currency": "USD"})})
+	gbpPlanId := api.put("plans", map[string]any{"planTemplateId": api.put("plantemplates", map[string]any{"currency": "GBP"})})
+
+	config := map[string]any{"plan_group_id": planGroupId, "plan_ids": []any{usdPlanId}}
+	state := p.create("m3ter_plan_group_links", config)
+
+	// Only the plan being added is looked up, and warned about
+	config["plan_ids"] = []any{usdPlanId, gbpPlanId}
+	planned, diags := p.plan("m3ter_plan_group_links", state, config)
+	p.checkDiagnostics("plan", diags)
+	api.clearRequests()
+	_, diags = p.apply("m3ter_plan_group_links", state, planned, config)
+	p.checkDiagnostics("update", diags)
+	summary := diagnosticsSummary(diags)
+	if !strings.Contains(summary, "Plan currency does not match plan group currency") || !strings.Contains(summary, gbpPlanId) {
+		t.Errorf("got diagnostics %q, want a currency mismatch warning for plan %s", summary, gbpPlanId)
+	}
+	if strings.Contains(summary, usdPlanId) {
+		t.Errorf("got diagnostics %q, want no warning for plan %s", summary, usdPlanId)
+	}
+	if n := len(api.requestsTo(http.MethodGet, "/plans/")); n != 1 {
+		t.Errorf("got %d plan lookups, want 1 for the added plan", n)
+	}
+	if n := len(api.requestsTo(http.MethodPost, "/plangrouplinks")); n != 1 {
+		t.Errorf("got %d links created, want 1", n)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"regexp"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -23,6 +24,8 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &PlanGroupResource{}
 var _ resource.ResourceWithImportState = &PlanGroupResource{}
+var _ resource.ResourceWithModifyPlan = &PlanGroupResource{}
+var _ resource.ResourceWithConfigValidators = &PlanGroupResource{}
 
 func NewPlanGroupResource() resource.Resource {
 	return &PlanGroupResource{}
@@ -35,20 +38,21 @@ type PlanGroupResource struct {
 
 // PlanGroupResourceModel describes the resource data model.
 type PlanGroupResourceModel struct {
-	Name                              types.String  `tfsdk:"name"`
-	Code                              types.String  `tfsdk:"code"`
-	CustomFields                      types.Dynamic `tfsdk:"custom_fields"`
-	MinimumSpend                      types.Float64 `tfsdk:"minimum_spend"`
-	MinimumSpendDescription           types.String  `tfsdk:"minimum_spend_description"`
-	StandingCharge                    types.Float64 `tfsdk:"standing_charge"`
-	StandingChargeDescription         types.String  `tfsdk:"standing_charge_description"`
-	Currency                          types.String  `tfsdk:"currency"`
-	StandingChargeBillInAdvance       types.Bool    `tfsdk:"standing_charge_bill_in_advance"`
-	MinimumSpendBillInAdvance         types.Bool    `tfsdk:"minimum_spend_bill_in_advance"`
-	MinimumSpendAccountingProductId   types.String  `tfsdk:"minimum_spend_accounting_product_id"`
-	StandingChargeAccountingProductId types.String  `tfsdk:"standing_charge_accounting_product_id"`
-	Id                                types.String  `tfsdk:"id"`
-	Version                           types.Int64   `tfsdk:"version"`
+	Name                              types.String   `tfsdk:"name"`
+	Code                              types.String   `tfsdk:"code"`
+	CustomFields                      types.Dynamic  `tfsdk:"custom_fields"`
+	MinimumSpend                      types.Float64  `tfsdk:"minimum_spend"`
+	MinimumSpendDescription           types.String   `tfsdk:"minimum_spend_description"`
+	StandingCharge                    types.Float64  `tfsdk:"standing_charge"`
+	StandingChargeDescription         types.String   `tfsdk:"standing_charge_description"`
+	Currency                          types.String   `tfsdk:"currency"`
+	StandingChargeBillInAdvance       types.Bool     `tfsdk:"standing_charge_bill_in_advance"`
+	MinimumSpendBillInAdvance         types.Bool     `tfsdk:"minimum_spend_bill_in_advance"`
+	MinimumSpendAccountingProductId   types.String   `tfsdk:"minimum_spend_accounting_product_id"`
+	StandingChargeAccountingProductId types.String   `tfsdk:"standing_charge_accounting_product_id"`
+	Id                                types.String   `tfsdk:"id"`
+	Version                           types.Int64    `tfsdk:"version"`
+	Timeouts                          timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *PlanGroupResourceModel) GetId() types.String {
@@ -80,7 +84,7 @@ func (r *PlanGroupResource) Schema(ctx context.Context, req resource.SchemaReque
 				},
 			},
 			"custom_fields": schema.DynamicAttribute{
-				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be either a string or a number.",
+				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be a string, a number or a boolean.",
 				Required:            true,
 			},
 
@@ -147,6 +151,9 @@ func (r *PlanGroupResource) Schema(ctx context.Context, req resource.SchemaReque
 				MarkdownDescription: "The version number.",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -190,6 +197,16 @@ func (r *PlanGroupResource) ImportState(ctx context.Context, req resource.Import
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+func (r *PlanGroupResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		minimumSpendDescription(),
+	}
+}
+
+func (r *PlanGroupResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkRequiredCustomFields(ctx, r.client, req.Plan, &resp.Diagnostics)
+}
+
 func (r *PlanGroupResource) read(ctx context.Context, data *PlanGroupResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
 	m := &mapper{
 		ctx:         ctx,
@@ -6,8 +6,11 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"regexp"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -25,6 +28,8 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &PlanResource{}
 var _ resource.ResourceWithImportState = &PlanResource{}
+var _ resource.ResourceWithModifyPlan = &PlanResource{}
+var _ resource.ResourceWithConfigValidators = &PlanResource{}
 
 func NewPlanResource() resource.Resource {
 	return &PlanResource{}
@@ -37,20 +42,21 @@ type PlanResource struct {
 
 // PlanResourceModel describes the resource data model.
 type PlanResourceModel struct {
-	Name                        types.String  `tfsdk:"name"`
-	Code                        types.String  `tfsdk:"code"`
-	CustomFields                types.Dynamic `tfsdk:"custom_fields"`
-	PlanTemplateId              types.String  `tfsdk:"plan_template_id"`
-	StandingCharge              types.Float64 `tfsdk:"standing_charge"`
-	StandingChargeDescription   types.String  `tfsdk:"standing_charge_description"`
-	Bespoke                     types.Bool    `tfsdk:"bespoke"`
-	MinimumSpend                types.Float64 `tfsdk:"minimum_spend"`
-	MinimumSpendDescription     types.String  `tfsdk:"minimum_spend_description"`
-	StandingChargeBillInAdvance types.Bool    `tfsdk:"standing_charge_bill_in_advance"`
-	MinimumSpendBillInAdvance   types.Bool    `tfsdk:"minimum_spend_bill_in_advance"`
-	AccountId                   types.String  `tfsdk:"account_id"`
-	Id                          types.String  `tfsdk:"id"`
-	Version                     types.Int64   `tfsdk:"version"`
+	Name                        types.String   `tfsdk:"name"`
+	Code                        types.String   `tfsdk:"code"`
+	CustomFields                types.Dynamic  `tfsdk:"custom_fields"`
+	PlanTemplateId              types.String   `tfsdk:"plan_template_id"`
+	StandingCharge              types.Float64  `tfsdk:"standing_charge"`
+	StandingChargeDescription   types.String   `tfsdk:"standing_charge_description"`
+	Bespoke                     types.Bool     `tfsdk:"bespoke"`
+	MinimumSpend                types.Float64  `tfsdk:"minimum_spend"`
+	MinimumSpendDescription     types.String   `tfsdk:"minimum_spend_description"`
+	StandingChargeBillInAdvance types.Bool     `tfsdk:"standing_charge_bill_in_advance"`
+	MinimumSpendBillInAdvance   types.Bool     `tfsdk:"minimum_spend_bill_in_advance"`
+	AccountId                   types.String   `tfsdk:"account_id"`
+	Id                          types.String   `tfsdk:"id"`
+	Version                     types.Int64    `tfsdk:"version"`
+	Timeouts                    timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *PlanResourceModel) GetId() types.String {
@@ -82,7 +88,7 @@ func (r *PlanResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 				},
 			},
 			"custom_fields": schema.DynamicAttribute{
-				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be either a string or a number.",
+				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be a string, a number or a boolean.",
 				Required:            true,
 			},
 			"plan_template_id": schema.StringAttribute{
@@ -157,6 +163,9 @@ func (r *PlanResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 				MarkdownDescription: "The version number.",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -193,13 +202,43 @@ func (r *PlanResource) Update(ctx context.Context, req resource.UpdateRequest, r
 }
 
 func (r *PlanResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	genericDelete[PlanResourceModel](ctx, req, resp, r.client, "/plans", "plan")
+	ctx, cancel := withOperationTimeout(ctx, req.State, "delete", &resp.Diagnostics)
+	defer cancel()
+
+	var data PlanResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.execute(ctx, "DELETE", "/plans/"+url.PathEscape(data.Id.ValueString()), nil, nil, nil)
+	// The plan was already deleted outside Terraform
+	if sc, ok := err.(*statusCodeError); ok && sc.StatusCode == 404 {
+		return
+	}
+	if isConflict(err) {
+		addPlanInUseError(ctx, r.client, "Plan", data.Id.ValueString(), nil, []string{data.Id.ValueString()}, err, &resp.Diagnostics)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete plan, got error: %s", err))
+	}
 }
 
 func (r *PlanResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+func (r *PlanResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		minimumSpendDescription(),
+	}
+}
+
+func (r *PlanResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkRequiredCustomFields(ctx, r.client, req.Plan, &resp.Diagnostics)
+}
+
 func (r *PlanResource) read(ctx context.Context, data *PlanResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
 	m := &mapper{
 		ctx:         ctx,
@@ -243,3 +282,31 @@ func (r *PlanResource) write(ctx context.Context, data *PlanResourceModel, restD
 	m.from(data.AccountId, "accountId")
 	m.customFieldsFrom(data.CustomFields)
 }
+
+// addPlanInUseError reports that the entity with the given id can't be deleted
+// because it is still in use, naming the plans based on it and the account
+// plans attaching attachedPlanIds to accounts where they can be found.
+func addPlanInUseError(ctx context.Context, client *m3terClient, name, id string, basedPlanIds, attachedPlanIds []string, err error, diagnostics *diag.Diagnostics) {
+	var accountPlanIds []string
+	for _, planId := range attachedPlanIds {
+		// The account plans are only listed to improve the message, so a
+		// failure to find them is ignored
+		ids, listErr := findDependents(ctx, client, "/accountplans", "planId", planId)
+		if listErr == nil {
+			accountPlanIds = append(accountPlanIds, ids...)
+		}
+	}
+
+	var detail string
+	switch {
+	case len(basedPlanIds) > 0 && len(accountPlanIds) > 0:
+		detail = fmt.Sprintf("%s %s is used by plans %s, which are attached to accounts through account plans %s. Detach the account plans and delete the plans first.", name, id, strings.Join(basedPlanIds, ", "), strings.Join(accountPlanIds, ", "))
+	case len(basedPlanIds) > 0:
+		detail = fmt.Sprintf("%s %s is used by plans %s, which must be deleted first.", name, id, strings.Join(basedPlanIds, ", "))
+	case len(accountPlanIds) > 0:
+		detail = fmt.Sprintf("%s %s is attached to accounts through account plans %s, which must be detached first.", name, id, strings.Join(accountPlanIds, ", "))
+	default:
+		detail = fmt.Sprintf("%s %s is in use and must be detached from its dependents first.", name, id)
+	}
+	diagnostics.AddError(name+" in use", fmt.Sprintf("%s Got error: %s", detail, err))
+}
@@ -0,0 +1,124 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &PlanTemplateDataSource{}
+
+func NewPlanTemplateDataSource() datasource.DataSource {
+	r := &PlanTemplateDataSource{}
+	r.genericDataSource = genericDataSource[PlanTemplateDataSourceModel, *PlanTemplateDataSourceModel]{
+		typeName: "plan_template",
+		path:     "/plantemplates",
+		name:     "plan template",
+		filters: func(data *PlanTemplateDataSourceModel) map[string]types.String {
+			return map[string]types.String{
+				"name": data.Name,
+				"code": data.Code,
+			}
+		},
+		extraQuery: func(data *PlanTemplateDataSourceModel) types.Map {
+			return data.ExtraQuery
+		},
+		read: r.read,
+	}
+	return r
+}
+
+// PlanTemplateDataSource defines the data source implementation.
+type PlanTemplateDataSource struct {
+	genericDataSource[PlanTemplateDataSourceModel, *PlanTemplateDataSourceModel]
+}
+
+type PlanTemplateDataSourceModel struct {
+	Name           types.String  `tfsdk:"name"`
+	Code           types.String  `tfsdk:"code"`
+	ProductId      types.String  `tfsdk:"product_id"`
+	Currency       types.String  `tfsdk:"currency"`
+	BillFrequency  types.String  `tfsdk:"bill_frequency"`
+	StandingCharge types.Float64 `tfsdk:"standing_charge"`
+	CustomFields   types.Dynamic `tfsdk:"custom_fields"`
+	Id             types.String  `tfsdk:"id"`
+	Version        types.Int64   `tfsdk:"version"`
+	ExtraQuery     types.Map     `tfsdk:"extra_query"`
+}
+
+func (r *PlanTemplateDataSourceModel) GetId() types.String {
+	return r.Id
+}
+
+func (r *PlanTemplateDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "PlanTemplate data source",
+
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Descriptive name for the PlanTemplate.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"code": schema.StringAttribute{
+				MarkdownDescription: "A unique, short code reference for the PlanTemplate.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"product_id": schema.StringAttribute{
+				MarkdownDescription: "The unique identifier (UUID) of the Product associated with this PlanTemplate.",
+				Computed:            true,
+			},
+			"currency": schema.StringAttribute{
+				MarkdownDescription: "The ISO currency code for the currency used to charge end users - for example USD, GBP, EUR.",
+				Computed:            true,
+			},
+			"bill_frequency": schema.StringAttribute{
+				MarkdownDescription: "Defines how often Bills are generated.",
+				Computed:            true,
+			},
+			"standing_charge": schema.Float64Attribute{
+				MarkdownDescription: "The fixed charge (standing charge) applied to customer bills.",
+				Computed:            true,
+			},
+			"custom_fields": schema.DynamicAttribute{
+				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be a string, a number or a boolean.",
+				Computed:            true,
+			},
+			"id": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The UUID of the entity.",
+			},
+			"version": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The version number.",
+			},
+			"extra_query": extraQueryAttribute,
+		},
+	}
+}
+
+func (r *PlanTemplateDataSource) read(ctx context.Context, data *PlanTemplateDataSourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           restData,
+	}
+	m.to("id", &data.Id)
+	m.to("version", &data.Version)
+	m.to("name", &data.Name)
+	m.to("code", &data.Code)
+	m.to("productId", &data.ProductId)
+	m.to("currency", &data.Currency)
+	m.to("billFrequency", &data.BillFrequency)
+	m.to("standingCharge", &data.StandingCharge)
+	m.customFieldsTo(&data.CustomFields)
+}
@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"regexp"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
@@ -26,6 +27,8 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &PlanTemplateResource{}
 var _ resource.ResourceWithImportState = &PlanTemplateResource{}
+var _ resource.ResourceWithModifyPlan = &PlanTemplateResource{}
+var _ resource.ResourceWithConfigValidators = &PlanTemplateResource{}
 
 func NewPlanTemplateResource() resource.Resource {
 	return &PlanTemplateResource{}
@@ -38,23 +41,24 @@ type PlanTemplateResource struct {
 
 // PlanTemplateResourceModel describes the resource data model.
 type PlanTemplateResourceModel struct {
-	Name                        types.String  `tfsdk:"name"`
-	Code                        types.String  `tfsdk:"code"`
-	CustomFields                types.Dynamic `tfsdk:"custom_fields"`
-	ProductId                   types.String  `tfsdk:"product_id"`
-	Currency                    types.String  `tfsdk:"currency"`
-	StandingCharge              types.Float64 `tfsdk:"standing_charge"`
-	StandingChargeDescription   types.String  `tfsdk:"standing_charge_description"`
-	StandingChargeInterval      types.Int32   `tfsdk:"standing_charge_interval"`
-	StandingChargeOffset        types.Int32   `tfsdk:"standing_charge_offset"`
-	BillFrequencyInterval       types.Int32   `tfsdk:"bill_frequency_interval"`
-	BillFrequency               types.String  `tfsdk:"bill_frequency"`
-	MinimumSpend                types.Float64 `tfsdk:"minimum_spend"`
-	MinimumSpendDescription     types.String  `tfsdk:"minimum_spend_description"`
-	StandingChargeBillInAdvance types.Bool    `tfsdk:"standing_charge_bill_in_advance"`
-	MinimumSpendBillInAdvance   types.Bool    `tfsdk:"minimum_spend_bill_in_advance"`
-	Id                          types.String  `tfsdk:"id"`
-	Version                     types.Int64   `tfsdk:"version"`
+	Name                        types.String   `tfsdk:"name"`
+	Code                        types.String   `tfsdk:"code"`
+	CustomFields                types.Dynamic  `tfsdk:"custom_fields"`
+	ProductId                   types.String   `tfsdk:"product_id"`
+	Currency                    types.String   `tfsdk:"currency"`
+	StandingCharge              types.Float64  `tfsdk:"standing_charge"`
+	StandingChargeDescription   types.String   `tfsdk:"standing_charge_description"`
+	StandingChargeInterval      types.Int32    `tfsdk:"standing_charge_interval"`
+	StandingChargeOffset        types.Int32    `tfsdk:"standing_charge_offset"`
+	BillFrequencyInterval       types.Int32    `tfsdk:"bill_frequency_interval"`
+	BillFrequency               types.String   `tfsdk:"bill_frequency"`
+	MinimumSpend                types.Float64  `tfsdk:"minimum_spend"`
+	MinimumSpendDescription     types.String   `tfsdk:"minimum_spend_description"`
+	StandingChargeBillInAdvance types.Bool     `tfsdk:"standing_charge_bill_in_advance"`
+	MinimumSpendBillInAdvance   types.Bool     `tfsdk:"minimum_spend_bill_in_advance"`
+	Id                          types.String   `tfsdk:"id"`
+	Version                     types.Int64    `tfsdk:"version"`
+	Timeouts                    timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *PlanTemplateResourceModel) GetId() types.String {
@@ -86,7 +90,7 @@ func (r *PlanTemplateResource) Schema(ctx context.Context, req resource.SchemaRe
 				},
 			},
 			"custom_fields": schema.DynamicAttribute{
-				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be either a string or a number.",
+				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be a string, a number or a boolean.",
 				Optional:            true,
 			},
 			"product_id": schema.StringAttribute{
@@ -126,11 +130,12 @@ func (r *PlanTemplateResource) Schema(ctx context.Context, req resource.SchemaRe
 				},
 			},
 			"standing_charge_offset": schema.Int32Attribute{
-				MarkdownDescription: "Defines an offset for when the standing charge is first applied. For example, if the bill is issued every three months and the standingChargeOfset is 0, then the charge is applied to the first bill (at three months); if 1, it would be applied to the second bill (at six months), and so on.",
+				MarkdownDescription: "Defines an offset for when the standing charge is first applied. For example, if the bill is issued every three months and the standingChargeOfset is 0, then the charge is applied to the first bill (at three months); if 1, it would be applied to the second bill (at six months), and so on. Requires standing_charge_interval to be set.",
 				Optional:            true,
 				Computed:            true,
 				Validators: []validator.Int32{
 					int32validator.Between(0, 364),
+					int32validator.AlsoRequires(path.MatchRoot("standing_charge_interval")),
 				},
 				PlanModifiers: []planmodifier.Int32{
 					int32planmodifier.UseStateForUnknown(),
@@ -139,9 +144,13 @@ func (r *PlanTemplateResource) Schema(ctx context.Context, req resource.SchemaRe
 			"bill_frequency_interval": schema.Int32Attribute{
 				MarkdownDescription: "How often bills are issued. For example, if billFrequency is Monthly and billFrequencyInterval is 3, bills are issued every three months.",
 				Optional:            true,
+				Computed:            true,
 				Validators: []validator.Int32{
 					int32validator.Between(1, 365),
 				},
+				PlanModifiers: []planmodifier.Int32{
+					int32planmodifier.UseStateForUnknown(),
+				},
 			},
 			"bill_frequency": schema.StringAttribute{
 				MarkdownDescription: "Defines how often Bills are generated.",
@@ -184,6 +193,9 @@ func (r *PlanTemplateResource) Schema(ctx context.Context, req resource.SchemaRe
 				MarkdownDescription: "The version number.",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -220,42 +232,43 @@ func (r *PlanTemplateResource) Update(ctx context.Context, req resource.UpdateRe
 }
 
 func (r *PlanTemplateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	genericDelete[PlanTemplateResourceModel](ctx, req, resp, r.client, "/plantemplates", "plan template")
+	ctx, cancel := withOperationTimeout(ctx, req.State, "delete", &resp.Diagnostics)
+	defer cancel()
+
+	var data PlanTemplateResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.execute(ctx, "DELETE", "/plantemplates/"+url.PathEscape(data.Id.ValueString()), nil, nil, nil)
+	// The plan template was already deleted outside Terraform
+	if sc, ok := err.(*statusCodeError); ok && sc.StatusCode == 404 {
+		return
+	}
+	if isConflict(err) {
+		// The template is in use through the plans based on it
+		planIds, _ := findDependents(ctx, r.client, "/plans", "planTemplateId", data.Id.ValueString())
+		addPlanInUseError(ctx, r.client, "Plan template", data.Id.ValueString(), planIds, planIds, err, &resp.Diagnostics)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete plan template, got error: %s", err))
+	}
 }
 
 func (r *PlanTemplateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	var restData map[string]any
-	err := r.client.execute(ctx, "GET", "/plantemplates/"+url.PathEscape(req.ID), nil, nil, &restData)
-	if sc, ok := err.(*statusCodeError); ok && sc.StatusCode == 404 {
-		urlValues := url.Values{}
-		urlValues.Set("pageSize", "200")
+	genericImportState(ctx, req, resp, r.client, "/plantemplates", "plan template", []string{"code", "name"})
+}
 
-		var listResponse struct {
-			Data []struct {
-				Id      string `json:"id"`
-				Name    string `json:"name"`
-				Code    string `json:"code"`
-				Version int64  `json:"version"`
-			} `json:"data"`
-			NextToken string `json:"next_token"`
-		}
-		err := r.client.execute(ctx, "GET", "/plantemplates", nil, nil, &listResponse)
-		if err != nil {
-			resp.Diagnostics.AddError("Failed to list plan templates", err.Error())
-			return
-		}
-		for _, meter := range listResponse.Data {
-			if meter.Code == req.ID {
-				resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), meter.Id)...)
-				return
-			} else if meter.Code == "" && meter.Name == req.ID {
-				resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), meter.Id)...)
-				return
-			}
-		}
-		resp.Diagnostics.AddError("Plan template not found", "The plan template with name or code "+req.ID+" does not exist.")
+func (r *PlanTemplateResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		minimumSpendDescription(),
 	}
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *PlanTemplateResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkRequiredCustomFields(ctx, r.client, req.Plan, &resp.Diagnostics)
 }
 
 func (r *PlanTemplateResource) read(ctx context.Context, data *PlanTemplateResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
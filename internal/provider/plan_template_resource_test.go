@@ -0,0 +1,113 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func testPlanTemplateConfig() map[string]any {
+	return map[string]any{
+		"name":            "Standard",
+		"code":            "standard",
+		"custom_fields":   map[string]any{},
+		"product_id":      "00000000-0000-4000-8000-000000000002",
+		"currency":        "USD",
+		"standing_charge": 0,
+		"bill_frequency":  "MONTHLY",
+	}
+}
+
+func TestPlanTemplateResourceDefaultBillFrequencyInterval(t *testing.T) {
+	api := newFakeAPI(t)
+	api.onWrite = func(collection string, entity map[string]any) {
+		if _, ok := entity["billFrequencyInterval"]; collection == "plantemplates" && !ok {
+			entity["billFrequencyInterval"] = float64(1)
+		}
+	}
+	p := newTestProvider(t, api, nil)
+
+	config := testPlanTemplateConfig()
+	state := p.create("m3ter_plan_template", config)
+	if v := attrValue(t, state, "bill_frequency_interval"); v != float64(1) {
+		t.Errorf("bill_frequency_interval = %v, want the API default 1", v)
+	}
+
+	state = p.read("m3ter_plan_template", state)
+	p.assertNoChanges("m3ter_plan_template", state, config)
+
+	config["name"] = "Standard plan"
+	state = p.update("m3ter_plan_template", state, config)
+	if v := attrValue(t, state, "bill_frequency_interval"); v != float64(1) {
+		t.Errorf("bill_frequency_interval = %v after update, want 1", v)
+	}
+}
+
+func TestPlanTemplateResourceDeleteInUse(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	state := p.create("m3ter_plan_template", testPlanTemplateConfig())
+	id := attrValue(t, state, "id").(string)
+	api.handle(func(w http.ResponseWriter, req *fakeRequest) bool {
+		if req.Method != http.MethodDelete || req.Path != "/plantemplates/"+id {
+			return false
+		}
+		writeJSON(w, http.StatusConflict, map[string]any{"message": "plan template is in use"})
+		return true
+	})
+
+	// Without dependents found, the API message is still reported
+	summary := diagnosticsSummary(p.destroy("m3ter_plan_template", state))
+	if !strings.Contains(summary, "Plan template in use") || !strings.Contains(summary, "must be detached from its dependents") || !strings.Contains(summary, "plan template is in use") {
+		t.Errorf("got diagnostics %q, want the plan template reported in use", summary)
+	}
+
+	planId := api.put("plans", map[string]any{"planTemplateId": id})
+	summary = diagnosticsSummary(p.destroy("m3ter_plan_template", state))
+	if !strings.Contains(summary, "is used by plans "+planId+", which must be deleted first") {
+		t.Errorf("got diagnostics %q, want the plan template reported in use by plan %s", summary, planId)
+	}
+
+	accountPlanId := api.put("accountplans", map[string]any{"planId": planId})
+	summary = diagnosticsSummary(p.destroy("m3ter_plan_template", state))
+	if !strings.Contains(summary, "attached to accounts through account plans "+accountPlanId) || !strings.Contains(summary, "plan template is in use") {
+		t.Errorf("got diagnostics %q, want plan %s reported attached through account plan %s", summary, planId, accountPlanId)
+	}
+}
+
+func TestPlanTemplateResourceOffsetWithoutInterval(t *testing.T) {
+	api := newFakeAPI(t)
+	api.onWrite = func(collection string, entity map[string]any) {
+		if _, ok := entity["standingChargeInterval"]; collection == "plantemplates" && !ok {
+			entity["standingChargeInterval"] = float64(1)
+			entity["standingChargeOffset"] = float64(0)
+		}
+	}
+	p := newTestProvider(t, api, nil)
+
+	config := testPlanTemplateConfig()
+	config["standing_charge_offset"] = 1
+	_, diags := p.plan("m3ter_plan_template", p.null("m3ter_plan_template"), config)
+	if summary := diagnosticsSummary(diags); !hasErrors(diags) || !strings.Contains(summary, "standing_charge_interval") {
+		t.Errorf("got diagnostics %q, want standing_charge_interval required with the offset", summary)
+	}
+
+	config["standing_charge_interval"] = 3
+	state := p.create("m3ter_plan_template", config)
+	state = p.read("m3ter_plan_template", state)
+	p.assertNoChanges("m3ter_plan_template", state, config)
+
+	// Without either, the server defaults are read back without a diff
+	config = testPlanTemplateConfig()
+	config["code"] = "defaulted"
+	state = p.create("m3ter_plan_template", config)
+	if v := attrValue(t, state, "standing_charge_interval"); v != float64(1) {
+		t.Errorf("standing_charge_interval = %v, want the API default 1", v)
+	}
+	state = p.read("m3ter_plan_template", state)
+	p.assertNoChanges("m3ter_plan_template", state, config)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &PlansDataSource{}
+var _ datasource.DataSourceWithConfigure = &PlansDataSource{}
+
+func NewPlansDataSource() datasource.DataSource {
+	r := &PlansDataSource{}
+	r.genericListDataSource = genericListDataSource[PlansDataSourceModel, plansEntryModel]{
+		typeName:        "plans",
+		path:            "/plans",
+		name:            "plan",
+		orderBy:         "code",
+		entryAttributes: plansEntryAttributes,
+		query: func(ctx context.Context, data *PlansDataSourceModel, diagnostics *diag.Diagnostics) url.Values {
+			query := url.Values{}
+			for field, value := range r.filters(data) {
+				if value != "" {
+					query.Set(field, value)
+				}
+			}
+			return query
+		},
+		match: func(data *PlansDataSourceModel, restData map[string]any) bool {
+			for field, value := range r.filters(data) {
+				if v, _ := restData[field].(string); value != "" && v != value {
+					return false
+				}
+			}
+			return true
+		},
+		extraQuery: func(data *PlansDataSourceModel) types.Map {
+			return data.ExtraQuery
+		},
+		entries: func(data *PlansDataSourceModel) *types.List {
+			return &data.Plans
+		},
+		read: r.read,
+	}
+	return r
+}
+
+// PlansDataSource defines the data source implementation.
+type PlansDataSource struct {
+	genericListDataSource[PlansDataSourceModel, plansEntryModel]
+}
+
+type PlansDataSourceModel struct {
+	PlanTemplateId types.String `tfsdk:"plan_template_id"`
+	AccountId      types.String `tfsdk:"account_id"`
+	Plans          types.List   `tfsdk:"plans"`
+	ExtraQuery     types.Map    `tfsdk:"extra_query"`
+}
+
+type plansEntryModel struct {
+	Id      types.String `tfsdk:"id"`
+	Name    types.String `tfsdk:"name"`
+	Code    types.String `tfsdk:"code"`
+	Bespoke types.Bool   `tfsdk:"bespoke"`
+	Version types.Int64  `tfsdk:"version"`
+}
+
+var plansEntryAttributes = map[string]schema.Attribute{
+	"id": schema.StringAttribute{
+		MarkdownDescription: "Plan identifier",
+		Computed:            true,
+	},
+	"name": schema.StringAttribute{
+		MarkdownDescription: "Descriptive name for the Plan.",
+		Computed:            true,
+	},
+	"code": schema.StringAttribute{
+		MarkdownDescription: "A unique short code to identify the Plan.",
+		Computed:            true,
+	},
+	"bespoke": schema.BoolAttribute{
+		MarkdownDescription: "Whether the Plan is a custom/bespoke Plan for a particular Account.",
+		Computed:            true,
+	},
+	"version": schema.Int64Attribute{
+		MarkdownDescription: "Plan version",
+		Computed:            true,
+	},
+}
+
+func (r *PlansDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Plans data source. Lists every plan, or those of a plan template or an account.",
+
+		Attributes: map[string]schema.Attribute{
+			"plan_template_id": schema.StringAttribute{
+				MarkdownDescription: "UUID of the Plan Template to list the Plans of.",
+				Optional:            true,
+			},
+			"account_id": schema.StringAttribute{
+				MarkdownDescription: "UUID of the Account to list the bespoke Plans of.",
+				Optional:            true,
+			},
+			"plans": schema.ListNestedAttribute{
+				MarkdownDescription: "The Plans found, ordered by code.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: plansEntryAttributes,
+				},
+			},
+			"extra_query": extraQueryAttribute,
+		},
+	}
+}
+
+// filters returns the filters of the config, keyed by API field.
+func (r *PlansDataSource) filters(data *PlansDataSourceModel) map[string]string {
+	return map[string]string{
+		"planTemplateId": data.PlanTemplateId.ValueString(),
+		"accountId":      data.AccountId.ValueString(),
+	}
+}
+
+func (r *PlansDataSource) read(ctx context.Context, entry *plansEntryModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           restData,
+	}
+	m.to("id", &entry.Id)
+	m.to("name", &entry.Name)
+	m.to("code", &entry.Code)
+	m.to("version", &entry.Version)
+	// A missing flag means the plan isn't bespoke
+	bespoke, _ := restData["bespoke"].(bool)
+	entry.Bespoke = types.BoolValue(bespoke)
+}
@@ -0,0 +1,173 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &PricingDataSource{}
+
+func NewPricingDataSource() datasource.DataSource {
+	r := &PricingDataSource{}
+	r.genericDataSource = genericDataSource[PricingDataSourceModel, *PricingDataSourceModel]{
+		typeName: "pricing",
+		path:     "/pricings",
+		name:     "pricing",
+		filters: func(data *PricingDataSourceModel) map[string]types.String {
+			return map[string]types.String{
+				"code": data.Code,
+			}
+		},
+		extraQuery: func(data *PricingDataSourceModel) types.Map {
+			return data.ExtraQuery
+		},
+		read: r.read,
+	}
+	return r
+}
+
+// PricingDataSource defines the data source implementation.
+type PricingDataSource struct {
+	genericDataSource[PricingDataSourceModel, *PricingDataSourceModel]
+}
+
+type PricingDataSourceModel struct {
+	Code                  types.String `tfsdk:"code"`
+	Description           types.String `tfsdk:"description"`
+	PlanId                types.String `tfsdk:"plan_id"`
+	PlanTemplateId        types.String `tfsdk:"plan_template_id"`
+	AggregationId         types.String `tfsdk:"aggregation_id"`
+	CompoundAggregationId types.String `tfsdk:"compound_aggregation_id"`
+	Type                  types.String `tfsdk:"type"`
+	StartDate             types.String `tfsdk:"start_date"`
+	EndDate               types.String `tfsdk:"end_date"`
+	PricingBands          types.List   `tfsdk:"pricing_bands"`
+	OveragePricingBands   types.List   `tfsdk:"overage_pricing_bands"`
+	Id                    types.String `tfsdk:"id"`
+	Version               types.Int64  `tfsdk:"version"`
+	ExtraQuery            types.Map    `tfsdk:"extra_query"`
+}
+
+func (r *PricingDataSourceModel) GetId() types.String {
+	return r.Id
+}
+
+var pricingDataSourceBandObject = schema.NestedAttributeObject{
+	Attributes: map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			MarkdownDescription: "The UUID of the pricing band.",
+			Computed:            true,
+		},
+		"lower_limit": schema.Float64Attribute{
+			MarkdownDescription: "The lower limit of the usage the band applies to.",
+			Computed:            true,
+		},
+		"fixed_price": schema.Float64Attribute{
+			MarkdownDescription: "The fixed price charged for the band.",
+			Computed:            true,
+		},
+		"unit_price": schema.Float64Attribute{
+			MarkdownDescription: "The price per unit of usage in the band.",
+			Computed:            true,
+		},
+	},
+}
+
+func (r *PricingDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Pricing data source",
+
+		Attributes: map[string]schema.Attribute{
+			"code": schema.StringAttribute{
+				MarkdownDescription: "Unique short code for the Pricing.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "Displayed on Bill line items.",
+				Computed:            true,
+			},
+			"plan_id": schema.StringAttribute{
+				MarkdownDescription: "UUID of the Plan the Pricing is for, if any.",
+				Computed:            true,
+			},
+			"plan_template_id": schema.StringAttribute{
+				MarkdownDescription: "UUID of the PlanTemplate the Pricing is for, if any.",
+				Computed:            true,
+			},
+			"aggregation_id": schema.StringAttribute{
+				MarkdownDescription: "UUID of the Aggregation the Pricing is for, if any.",
+				Computed:            true,
+			},
+			"compound_aggregation_id": schema.StringAttribute{
+				MarkdownDescription: "UUID of the Compound Aggregation the Pricing is for, if any.",
+				Computed:            true,
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "The type of the pricing.",
+				Computed:            true,
+			},
+			"start_date": schema.StringAttribute{
+				MarkdownDescription: "The start date (in ISO-8601 format) for when the Pricing starts to be active for the Plan or PlanTemplate.",
+				Computed:            true,
+			},
+			"end_date": schema.StringAttribute{
+				MarkdownDescription: "The end date (in ISO-8601 format) for when the Pricing ceases to be active for the Plan or PlanTemplate, if any.",
+				Computed:            true,
+			},
+			"pricing_bands": schema.ListNestedAttribute{
+				MarkdownDescription: "The pricing bands of the pricing.",
+				Computed:            true,
+				NestedObject:        pricingDataSourceBandObject,
+			},
+			"overage_pricing_bands": schema.ListNestedAttribute{
+				MarkdownDescription: "The Prepayment/Balance overage pricing bands of the pricing.",
+				Computed:            true,
+				NestedObject:        pricingDataSourceBandObject,
+			},
+			"id": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The UUID of the entity.",
+			},
+			"version": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The version number.",
+			},
+			"extra_query": extraQueryAttribute,
+		},
+	}
+}
+
+func (r *PricingDataSource) read(ctx context.Context, data *PricingDataSourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           restData,
+	}
+	m.to("id", &data.Id)
+	m.to("version", &data.Version)
+	m.to("code", &data.Code)
+	m.to("description", &data.Description)
+	m.to("planId", &data.PlanId)
+	m.to("planTemplateId", &data.PlanTemplateId)
+	m.to("aggregationId", &data.AggregationId)
+	m.to("compoundAggregationId", &data.CompoundAggregationId)
+	m.to("type", &data.Type)
+	m.to("startDate", &data.StartDate)
+	m.to("endDate", &data.EndDate)
+
+	// A null list of bands is treated as empty
+	bands, _ := restData["pricingBands"].([]any)
+	data.PricingBands = readPricingBandList(bands, diagnostics)
+	overageBands, _ := restData["overagePricingBands"].([]any)
+	data.OveragePricingBands = readPricingBandList(overageBands, diagnostics)
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"regexp"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
@@ -26,6 +27,8 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &PricingResource{}
 var _ resource.ResourceWithImportState = &PricingResource{}
+var _ resource.ResourceWithModifyPlan = &PricingResource{}
+var _ resource.ResourceWithConfigValidators = &PricingResource{}
 
 func NewPricingResource() resource.Resource {
 	return &PricingResource{}
@@ -38,25 +41,27 @@ type PricingResource struct {
 
 // PricingResourceModel describes the resource data model.
 type PricingResourceModel struct {
-	Description               types.String  `tfsdk:"description"`
-	Code                      types.String  `tfsdk:"code"`
-	AggregationId             types.String  `tfsdk:"aggregation_id"`
-	CompoundAggregationId     types.String  `tfsdk:"compound_aggregation_id"`
-	Type                      types.String  `tfsdk:"type"`
-	Segment                   types.Map     `tfsdk:"segment"`
-	TiersSpanPlan             types.Bool    `tfsdk:"tiers_span_plan"`
-	MinimumSpend              types.Float64 `tfsdk:"minimum_spend"`
-	MinimumSpendDescription   types.String  `tfsdk:"minimum_spend_description"`
-	MinimumSpendBillInAdvance types.Bool    `tfsdk:"minimum_spend_bill_in_advance"`
-	OveragePricingBands       types.List    `tfsdk:"overage_pricing_bands"`
-	PlanId                    types.String  `tfsdk:"plan_id"`
-	PlanTemplateId            types.String  `tfsdk:"plan_template_id"`
-	Cumulative                types.Bool    `tfsdk:"cumulative"`
-	StartDate                 types.String  `tfsdk:"start_date"`
-	EndDate                   types.String  `tfsdk:"end_date"`
-	PricingBands              types.List    `tfsdk:"pricing_bands"`
-	Id                        types.String  `tfsdk:"id"`
-	Version                   types.Int64   `tfsdk:"version"`
+	Description               types.String   `tfsdk:"description"`
+	Code                      types.String   `tfsdk:"code"`
+	AggregationId             types.String   `tfsdk:"aggregation_id"`
+	CompoundAggregationId     types.String   `tfsdk:"compound_aggregation_id"`
+	Type                      types.String   `tfsdk:"type"`
+	Segment                   types.Map      `tfsdk:"segment"`
+	TiersSpanPlan             types.Bool     `tfsdk:"tiers_span_plan"`
+	MinimumSpend              types.Float64  `tfsdk:"minimum_spend"`
+	MinimumSpendDescription   types.String   `tfsdk:"minimum_spend_description"`
+	MinimumSpendBillInAdvance types.Bool     `tfsdk:"minimum_spend_bill_in_advance"`
+	OveragePricingBands       types.List     `tfsdk:"overage_pricing_bands"`
+	PlanId                    types.String   `tfsdk:"plan_id"`
+	PlanTemplateId            types.String   `tfsdk:"plan_template_id"`
+	Cumulative                types.Bool     `tfsdk:"cumulative"`
+	StartDate                 types.String   `tfsdk:"start_date"`
+	EndDate                   types.String   `tfsdk:"end_date"`
+	PricingBands              types.List     `tfsdk:"pricing_bands"`
+	AccountingProductId       types.String   `tfsdk:"accounting_product_id"`
+	Id                        types.String   `tfsdk:"id"`
+	Version                   types.Int64    `tfsdk:"version"`
+	Timeouts                  timeouts.Value `tfsdk:"timeouts"`
 }
 
 var pricingBandNestedObject = schema.NestedAttributeObject{
@@ -96,8 +101,12 @@ func (r *PricingResource) Schema(ctx context.Context, req resource.SchemaRequest
 
 		Attributes: map[string]schema.Attribute{
 			"description": schema.StringAttribute{
-				MarkdownDescription: "Displayed on Bill line items.",
+				MarkdownDescription: "Displayed on Bill line items. When not set, m3ter may default it, such as from the code.",
 				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 				Validators: []validator.String{
 					stringvalidator.LengthAtMost(200),
 				},
@@ -125,7 +134,7 @@ func (r *PricingResource) Schema(ctx context.Context, req resource.SchemaRequest
 				},
 			},
 			"type": schema.StringAttribute{
-				MarkdownDescription: "The type of the pricing.",
+				MarkdownDescription: "The type of the pricing. Defaults to DEBIT for new pricings of an aggregation or compound aggregation.",
 				Optional:            true,
 				Computed:            true,
 				Validators: []validator.String{
@@ -141,7 +150,7 @@ func (r *PricingResource) Schema(ctx context.Context, req resource.SchemaRequest
 				ElementType:         types.StringType,
 			},
 			"tiers_span_plan": schema.BoolAttribute{
-				MarkdownDescription: "If TRUE, usage accumulates over the entire period the priced Plan is active for the account, and is not reset for pricing band rates at the start of each billing period.\n\nIf FALSE, usage does not accumulate, and is reset for pricing bands at the start of each billing period.",
+				MarkdownDescription: "If TRUE, usage accumulates over the entire period the priced Plan is active for the account, and is not reset for pricing band rates at the start of each billing period.\n\nIf FALSE, usage does not accumulate, and is reset for pricing bands at the start of each billing period.\n\nRequires `cumulative` to be true when TRUE.",
 				Optional:            true,
 				Computed:            true,
 				Default:             booldefault.StaticBool(false),
@@ -187,7 +196,7 @@ func (r *PricingResource) Schema(ctx context.Context, req resource.SchemaRequest
 				},
 			},
 			"cumulative": schema.BoolAttribute{
-				MarkdownDescription: "Controls whether or not charge rates under a set of pricing bands configured for a Pricing are applied according to each separate band or at the highest band reached.",
+				MarkdownDescription: "Controls whether or not charge rates under a set of pricing bands configured for a Pricing are applied according to each separate band or at the highest band reached.\n\nShould be true when `tiers_span_plan` is true, as charging all usage at the rate of the highest band reached may reprice usage already billed in earlier billing periods. Setting it to false with `tiers_span_plan` true plans with a warning.",
 				Optional:            true,
 				Computed:            true,
 				Default:             booldefault.StaticBool(false),
@@ -198,16 +207,26 @@ func (r *PricingResource) Schema(ctx context.Context, req resource.SchemaRequest
 			"start_date": schema.StringAttribute{
 				MarkdownDescription: "The start date (in ISO-8601 format) for when the Pricing starts to be active for the Plan of Plan Template.",
 				Required:            true,
+				Validators: []validator.String{
+					iso8601(),
+				},
 			},
 			"end_date": schema.StringAttribute{
-				MarkdownDescription: "The end date (in ISO-8601 format) for when the Pricing ceases to be active for the Plan or Plan Template.",
+				MarkdownDescription: "The end date (in ISO-8601 format) for when the Pricing ceases to be active for the Plan or Plan Template. If omitted or empty, the Pricing is open-ended.",
 				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.Any(stringvalidator.OneOf(""), iso8601()),
+				},
 			},
 			"pricing_bands": schema.ListNestedAttribute{
 				MarkdownDescription: "The pricing bands of the pricing.",
 				Required:            true,
 				NestedObject:        pricingBandNestedObject,
 			},
+			"accounting_product_id": schema.StringAttribute{
+				MarkdownDescription: "Optional Product ID this Pricing should be attributed to for accounting purposes.",
+				Optional:            true,
+			},
 			"id": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "The UUID of the entity.",
@@ -220,6 +239,9 @@ func (r *PricingResource) Schema(ctx context.Context, req resource.SchemaRequest
 				MarkdownDescription: "The version number.",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -260,9 +282,38 @@ func (r *PricingResource) Delete(ctx context.Context, req resource.DeleteRequest
 }
 
 func (r *PricingResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// The read following the import populates the pricing band ids from the
+	// API, which the ids' UseStateForUnknown then carries into the next plan
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+func (r *PricingResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		minimumSpendDescription(),
+		tiersSpanPlan(),
+	}
+}
+
+func (r *PricingResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Only new pricings need a type inferred, existing ones keep theirs
+	if req.Plan.Raw.IsNull() || !req.State.Raw.IsNull() {
+		return
+	}
+
+	var data PricingResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() || !data.Type.IsUnknown() {
+		return
+	}
+
+	// Usage based pricings are debits, so infer that rather than leaving the
+	// type to the API's default. An unknown aggregation id, such as that of an
+	// aggregation created in the same apply, is set all the same.
+	if !data.AggregationId.IsNull() || !data.CompoundAggregationId.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("type"), "DEBIT")...)
+	}
+}
+
 func (r *PricingResource) read(ctx context.Context, data *PricingResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
 	m := &mapper{
 		ctx:         ctx,
@@ -272,10 +323,14 @@ func (r *PricingResource) read(ctx context.Context, data *PricingResourceModel,
 	m.to("id", &data.Id)
 	m.to("version", &data.Version)
 	m.to("description", &data.Description)
+	if data.Description.IsUnknown() {
+		data.Description = types.StringNull()
+	}
 	m.to("code", &data.Code)
 	m.to("aggregationId", &data.AggregationId)
 	m.to("compoundAggregationId", &data.CompoundAggregationId)
 	m.to("type", &data.Type)
+	markMapped(ctx, "segment", "overagePricingBands", "endDate", "pricingBands")
 	if segments, ok := restData["segment"].(map[string]any); ok {
 		elements := make(map[string]attr.Value)
 		for k, v := range segments {
@@ -305,11 +360,20 @@ func (r *PricingResource) read(ctx context.Context, data *PricingResourceModel,
 	m.to("planTemplateId", &data.PlanTemplateId)
 	m.to("cumulative", &data.Cumulative)
 	m.to("startDate", &data.StartDate)
-	m.to("endDate", &data.EndDate)
-	if bands, ok := restData["pricingBands"].([]any); ok {
+	// A missing end date means the pricing is open-ended, which the config may
+	// express as either null or an empty string
+	if endDate, ok := restData["endDate"].(string); ok && endDate != "" {
+		data.EndDate = types.StringValue(endDate)
+	} else if data.EndDate.IsUnknown() || data.EndDate.ValueString() != "" {
+		data.EndDate = types.StringNull()
+	}
+	if bands, ok := restData["pricingBands"]; ok {
+		// A null list of bands is treated as empty
+		bands, _ := bands.([]any)
 		lv := readPricingBandList(bands, diagnostics)
 		data.PricingBands = lv
 	}
+	m.to("accountingProductId", &data.AccountingProductId)
 }
 
 func (r *PricingResource) write(ctx context.Context, data *PricingResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
@@ -347,11 +411,19 @@ func (r *PricingResource) write(ctx context.Context, data *PricingResourceModel,
 	m.from(data.PlanTemplateId, "planTemplateId")
 	m.from(data.Cumulative, "cumulative")
 	m.from(data.StartDate, "startDate")
-	m.from(data.EndDate, "endDate")
+	if data.EndDate.IsNull() || (!data.EndDate.IsUnknown() && data.EndDate.ValueString() == "") {
+		delete(restData, "endDate")
+	} else {
+		m.from(data.EndDate, "endDate")
+	}
 	if bands := data.PricingBands; !bands.IsUnknown() {
 		bandList := writePricingBandList(bands, diagnostics)
 		m.v["pricingBands"] = bandList
 	}
+	m.from(data.AccountingProductId, "accountingProductId")
+	if data.AccountingProductId.IsNull() {
+		delete(m.v, "accountingProductId")
+	}
 }
 
 func writePricingBandList(bands types.List, diagnostics *diag.Diagnostics) []any {
@@ -407,17 +479,18 @@ func readPricingBandList(bands []any, diagnostics *diag.Diagnostics) types.List
 				diagnostics.AddError("Invalid overage pricing band", "Pricing band must have an id")
 			}
 
+			// The API may omit zero limits and prices, or return them as null
 			lowerLimit, ok := b["lowerLimit"].(float64)
-			if !ok {
-				diagnostics.AddError("Invalid overage pricing band", "Pricing band must have a lower limit")
+			if !ok && b["lowerLimit"] != nil {
+				diagnostics.AddError("Invalid overage pricing band", "Pricing band lower limit must be a number")
 			}
 			fixedPrice, ok := b["fixedPrice"].(float64)
-			if !ok {
-				diagnostics.AddError("Invalid overage pricing band", "Pricing band must have a fixed price")
+			if !ok && b["fixedPrice"] != nil {
+				diagnostics.AddError("Invalid overage pricing band", "Pricing band fixed price must be a number")
 			}
 			unitPrice, ok := b["unitPrice"].(float64)
-			if !ok {
-				diagnostics.AddError("Invalid overage pricing band", "Pricing band must have a unit price")
+			if !ok && b["unitPrice"] != nil {
+				diagnostics.AddError("Invalid overage pricing band", "Pricing band unit price must be a number")
 			}
 
 			band, diag := types.ObjectValue(map[string]attr.Type{
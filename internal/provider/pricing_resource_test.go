@@ -0,0 +1,213 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func testPricingConfig() map[string]any {
+	return map[string]any{
+		"plan_id":        "00000000-0000-4000-8000-000000000002",
+		"aggregation_id": "00000000-0000-4000-8000-000000000003",
+		"start_date":     "2024-01-01T00:00:00Z",
+		"pricing_bands": []any{
+			map[string]any{"lower_limit": 0, "fixed_price": 0, "unit_price": 0.5},
+		},
+	}
+}
+
+func TestPricingResourceClearAccountingProductId(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	config := testPricingConfig()
+	config["accounting_product_id"] = "00000000-0000-4000-8000-000000000004"
+	state := p.create("m3ter_pricing", config)
+	id := attrValue(t, state, "id").(string)
+
+	delete(config, "accounting_product_id")
+	state = p.update("m3ter_pricing", state, config)
+	if _, ok := api.get("pricings", id)["accountingProductId"]; ok {
+		t.Errorf("accountingProductId was sent after being cleared")
+	}
+
+	state = p.read("m3ter_pricing", state)
+	if v := attrValue(t, state, "accounting_product_id"); v != nil {
+		t.Errorf("accounting_product_id = %v, want null", v)
+	}
+	p.assertNoChanges("m3ter_pricing", state, config)
+}
+
+func TestPricingResourceOpenEnded(t *testing.T) {
+	for _, endDate := range []any{nil, ""} {
+		for _, returned := range []any{nil, ""} {
+			api := newFakeAPI(t)
+			// The API may return the missing end date as null or empty
+			api.onWrite = func(collection string, entity map[string]any) {
+				if _, ok := entity["endDate"]; !ok {
+					entity["endDate"] = returned
+				}
+			}
+			p := newTestProvider(t, api, nil)
+
+			config := testPricingConfig()
+			config["end_date"] = endDate
+			state := p.create("m3ter_pricing", config)
+			if _, ok := api.requestsTo(http.MethodPost, "/pricings")[0].Body["endDate"]; ok {
+				t.Errorf("end_date %q: an end date was sent", endDate)
+			}
+
+			state = p.read("m3ter_pricing", state)
+			if v := attrValue(t, state, "end_date"); v != endDate {
+				t.Errorf("end_date %q, returned %q: read %q", endDate, returned, v)
+			}
+			p.assertNoChanges("m3ter_pricing", state, config)
+		}
+	}
+}
+
+func TestPricingResourceNullBandValues(t *testing.T) {
+	api := newFakeAPI(t)
+	// The API returns null for the zero limits and prices of a band
+	api.onWrite = func(collection string, entity map[string]any) {
+		if collection != "pricings" {
+			return
+		}
+		for _, band := range entity["pricingBands"].([]any) {
+			band := band.(map[string]any)
+			for _, key := range []string{"lowerLimit", "fixedPrice"} {
+				if band[key] == float64(0) {
+					band[key] = nil
+				}
+			}
+		}
+	}
+	p := newTestProvider(t, api, nil)
+
+	config := testPricingConfig()
+	state := p.create("m3ter_pricing", config)
+	state = p.read("m3ter_pricing", state)
+	if v := attrValue(t, state, "pricing_bands.0.lower_limit"); v != float64(0) {
+		t.Errorf("pricing_bands.0.lower_limit = %v, want 0", v)
+	}
+	p.assertNoChanges("m3ter_pricing", state, config)
+
+	// Null bands read as an empty list, which is then planned back
+	id := attrValue(t, state, "id").(string)
+	stored := api.get("pricings", id)
+	stored["pricingBands"] = nil
+	state = p.read("m3ter_pricing", state)
+	if v := attrValue(t, state, "pricing_bands"); v != 0 {
+		t.Errorf("pricing_bands has %v elements, want 0", v)
+	}
+}
+
+func TestPricingResourceImportBandIds(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	id := api.put("pricings", map[string]any{
+		"planId":        "00000000-0000-4000-8000-000000000002",
+		"aggregationId": "00000000-0000-4000-8000-000000000003",
+		"startDate":     "2024-01-01T00:00:00Z",
+		"type":          "DEBIT",
+		"cumulative":    false,
+		"tiersSpanPlan": false,
+		"pricingBands": []any{
+			map[string]any{"id": "band-1", "lowerLimit": float64(0), "fixedPrice": float64(0), "unitPrice": 0.5},
+			map[string]any{"id": "band-2", "lowerLimit": float64(100), "fixedPrice": float64(0), "unitPrice": 0.25},
+		},
+	})
+
+	state := p.importState("m3ter_pricing", id)
+	for i, want := range []string{"band-1", "band-2"} {
+		if v := attrValue(t, state, fmt.Sprintf("pricing_bands.%d.id", i)); v != want {
+			t.Errorf("pricing_bands.%d.id = %v, want %s", i, v, want)
+		}
+	}
+
+	config := testPricingConfig()
+	config["pricing_bands"] = []any{
+		map[string]any{"lower_limit": 0, "fixed_price": 0, "unit_price": 0.5},
+		map[string]any{"lower_limit": 100, "fixed_price": 0, "unit_price": 0.25},
+	}
+	p.assertNoChanges("m3ter_pricing", state, config)
+}
+
+func TestPricingResourceInferType(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	// The aggregation is created in the same apply, so its id is unknown
+	config := testPricingConfig()
+	config["aggregation_id"] = unknown
+	planned, diags := p.plan("m3ter_pricing", p.null("m3ter_pricing"), config)
+	p.checkDiagnostics("plan", diags)
+	if v := attrValue(t, planned, "type"); v != "DEBIT" {
+		t.Errorf("type = %v, want DEBIT inferred for a usage based pricing", v)
+	}
+
+	// Without an aggregation the type is left to the API
+	delete(config, "aggregation_id")
+	planned, diags = p.plan("m3ter_pricing", p.null("m3ter_pricing"), config)
+	p.checkDiagnostics("plan", diags)
+	if v := attrValue(t, planned, "type"); v != unknown {
+		t.Errorf("type = %v, want it unknown until apply", v)
+	}
+}
+
+func TestPricingResourceDefaultDescription(t *testing.T) {
+	api := newFakeAPI(t)
+	// The API defaults the description to the code, if any
+	api.onWrite = func(collection string, entity map[string]any) {
+		if _, ok := entity["description"]; collection == "pricings" && !ok && entity["code"] != nil {
+			entity["description"] = entity["code"]
+		}
+	}
+	p := newTestProvider(t, api, nil)
+
+	config := testPricingConfig()
+	config["code"] = "api_calls"
+	state := p.create("m3ter_pricing", config)
+	if v := attrValue(t, state, "description"); v != "api_calls" {
+		t.Errorf("description = %v, want the API default api_calls", v)
+	}
+	state = p.read("m3ter_pricing", state)
+	p.assertNoChanges("m3ter_pricing", state, config)
+
+	// Without a code the description stays null
+	config = testPricingConfig()
+	state = p.create("m3ter_pricing", config)
+	if v := attrValue(t, state, "description"); v != nil {
+		t.Errorf("description = %v, want null", v)
+	}
+	state = p.read("m3ter_pricing", state)
+	p.assertNoChanges("m3ter_pricing", state, config)
+}
+
+func TestPricingResourceTiersSpanPlanNonCumulative(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	// The combination is discouraged but not rejected
+	config := testPricingConfig()
+	config["tiers_span_plan"] = true
+	config["cumulative"] = false
+	_, diags := p.plan("m3ter_pricing", p.null("m3ter_pricing"), config)
+	p.checkDiagnostics("plan", diags)
+	if summary := diagnosticsSummary(diags); !strings.Contains(summary, "Non-cumulative pricing spanning the plan") {
+		t.Errorf("diagnostics = %q, want a warning about non-cumulative pricing", summary)
+	}
+
+	config["cumulative"] = true
+	_, diags = p.plan("m3ter_pricing", p.null("m3ter_pricing"), config)
+	p.checkDiagnostics("plan", diags)
+	if len(diags) != 0 {
+		t.Errorf("diagnostics = %q, want none", diagnosticsSummary(diags))
+	}
+}
@@ -6,12 +6,11 @@ package provider
 import (
 	"context"
 	"fmt"
-	"net/url"
 	"regexp"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -23,6 +22,7 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &ProductResource{}
 var _ resource.ResourceWithImportState = &ProductResource{}
+var _ resource.ResourceWithModifyPlan = &ProductResource{}
 
 func NewProductResource() resource.Resource {
 	return &ProductResource{}
@@ -35,11 +35,12 @@ type ProductResource struct {
 
 // ProductResourceModel describes the resource data model.
 type ProductResourceModel struct {
-	Name         types.String  `tfsdk:"name"`
-	Code         types.String  `tfsdk:"code"`
-	CustomFields types.Dynamic `tfsdk:"custom_fields"`
-	Id           types.String  `tfsdk:"id"`
-	Version      types.Int64   `tfsdk:"version"`
+	Name         types.String   `tfsdk:"name"`
+	Code         types.String   `tfsdk:"code"`
+	CustomFields types.Dynamic  `tfsdk:"custom_fields"`
+	Id           types.String   `tfsdk:"id"`
+	Version      types.Int64    `tfsdk:"version"`
+	Timeouts     timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *ProductResourceModel) GetId() types.String {
@@ -71,7 +72,7 @@ func (r *ProductResource) Schema(ctx context.Context, req resource.SchemaRequest
 				},
 			},
 			"custom_fields": schema.DynamicAttribute{
-				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be either a string or a number.",
+				MarkdownDescription: "User defined fields enabling you to attach custom data. The value for a custom field can be a string, a number or a boolean.",
 				Required:            true,
 			},
 			"id": schema.StringAttribute{
@@ -86,6 +87,9 @@ func (r *ProductResource) Schema(ctx context.Context, req resource.SchemaRequest
 				MarkdownDescription: "The version number",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -126,40 +130,11 @@ func (r *ProductResource) Delete(ctx context.Context, req resource.DeleteRequest
 }
 
 func (r *ProductResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	var restData map[string]any
-	err := r.client.execute(ctx, "GET", "/products/"+url.PathEscape(req.ID), nil, nil, &restData)
-	if sc, ok := err.(*statusCodeError); ok && sc.StatusCode == 404 {
-		urlValues := url.Values{}
-		urlValues.Set("pageSize", "200")
-		for {
-			var productListResponse struct {
-				Data []struct {
-					Id      string `json:"id"`
-					Code    string `json:"code"`
-					Version int64  `json:"version"`
-				} `json:"data"`
-				NextToken string `json:"next_token"`
-			}
-			err := r.client.execute(ctx, "GET", "/products", nil, nil, &productListResponse)
-			if err != nil {
-				resp.Diagnostics.AddError("Failed to list products", err.Error())
-				return
-			}
-			for _, product := range productListResponse.Data {
-				if product.Code == req.ID {
-					resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), product.Id)...)
-					return
-				}
-			}
-			if productListResponse.NextToken == "" {
-				break
-			}
-			urlValues.Set("nextToken", productListResponse.NextToken)
-		}
-
-		resp.Diagnostics.AddError("Product not found", "The product with code "+req.ID+" does not exist.")
-	}
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	genericImportState(ctx, req, resp, r.client, "/products", "product", []string{"code"})
+}
+
+func (r *ProductResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkRequiredCustomFields(ctx, r.client, req.Plan, &resp.Diagnostics)
 }
 
 func (r *ProductResource) read(ctx context.Context, data *ProductResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
@@ -0,0 +1,42 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProductResourceCustomFieldTypes(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	config := map[string]any{
+		"name": "Product",
+		"code": "product",
+		"custom_fields": map[string]any{
+			"team":     "billing",
+			"priority": 2,
+			"billable": true,
+			"archived": false,
+		},
+	}
+	state := p.create("m3ter_product", config)
+	id := attrValue(t, state, "id").(string)
+
+	want := map[string]any{"team": "billing", "priority": float64(2), "billable": true, "archived": false}
+	if got := api.get("products", id)["customFields"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("sent customFields %v, want %v", got, want)
+	}
+	for name, value := range want {
+		if v := attrValue(t, state, "custom_fields."+name); v != value {
+			t.Errorf("custom_fields.%s = %v, want %v", name, v, value)
+		}
+	}
+	state = p.read("m3ter_product", state)
+	p.assertNoChanges("m3ter_product", state, config)
+
+	imported := p.importState("m3ter_product", id)
+	p.assertNoChanges("m3ter_product", imported, config)
+}
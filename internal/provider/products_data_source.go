@@ -0,0 +1,211 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ProductsDataSource{}
+var _ datasource.DataSourceWithConfigure = &ProductsDataSource{}
+
+func NewProductsDataSource() datasource.DataSource {
+	r := &ProductsDataSource{}
+	r.genericListDataSource = genericListDataSource[ProductsDataSourceModel, productsEntryModel]{
+		typeName:        "products",
+		path:            "/products",
+		name:            "product",
+		orderBy:         "code",
+		entryAttributes: productsEntryAttributes,
+		query: func(ctx context.Context, data *ProductsDataSourceModel, diagnostics *diag.Diagnostics) url.Values {
+			query := url.Values{}
+			if codes := r.codes(ctx, data, diagnostics); len(codes) > 0 {
+				query["codes"] = codes
+			}
+			return query
+		},
+		match: func(data *ProductsDataSourceModel, restData map[string]any) bool {
+			code, ok := restData["code"].(string)
+			if !ok || !strings.HasPrefix(code, data.CodePrefix.ValueString()) {
+				return false
+			}
+			// The codes are checked in case the API ignores the filter
+			return data.Codes.IsNull() || slices.ContainsFunc(data.Codes.Elements(), func(v attr.Value) bool {
+				return v.Equal(types.StringValue(code))
+			})
+		},
+		extraQuery: func(data *ProductsDataSourceModel) types.Map {
+			return data.ExtraQuery
+		},
+		entries: func(data *ProductsDataSourceModel) *types.List {
+			return &data.Products
+		},
+		read: r.read,
+		done: r.done,
+	}
+	return r
+}
+
+// ProductsDataSource defines the data source implementation.
+type ProductsDataSource struct {
+	genericListDataSource[ProductsDataSourceModel, productsEntryModel]
+}
+
+type ProductsDataSourceModel struct {
+	Codes      types.Set    `tfsdk:"codes"`
+	CodePrefix types.String `tfsdk:"code_prefix"`
+	Products   types.List   `tfsdk:"products"`
+	Ids        types.Map    `tfsdk:"ids"`
+	ExtraQuery types.Map    `tfsdk:"extra_query"`
+}
+
+type productsEntryModel struct {
+	Id           types.String         `tfsdk:"id"`
+	Name         types.String         `tfsdk:"name"`
+	Code         types.String         `tfsdk:"code"`
+	CustomFields jsontypes.Normalized `tfsdk:"custom_fields"`
+	Version      types.Int64          `tfsdk:"version"`
+}
+
+var productsEntryAttributes = map[string]schema.Attribute{
+	"id": schema.StringAttribute{
+		MarkdownDescription: "Product identifier",
+		Computed:            true,
+	},
+	"name": schema.StringAttribute{
+		MarkdownDescription: "Descriptive name for the Product providing context and information.",
+		Computed:            true,
+	},
+	"code": schema.StringAttribute{
+		MarkdownDescription: "A unique short code to identify the Product.",
+		Computed:            true,
+	},
+	"custom_fields": schema.StringAttribute{
+		MarkdownDescription: "User defined fields attached to the Product, as a JSON object keeping the string, number and boolean types of the values. Decode it with `jsondecode`.",
+		Computed:            true,
+		// Dynamic values, which custom fields are elsewhere, can't be nested
+		// in a list
+		CustomType: jsontypes.NormalizedType{},
+	},
+	"version": schema.Int64Attribute{
+		MarkdownDescription: "Product version",
+		Computed:            true,
+	},
+}
+
+func (r *ProductsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Products data source. Looks up many products by code in a single pass, or lists every product.",
+
+		Attributes: map[string]schema.Attribute{
+			"codes": schema.SetAttribute{
+				MarkdownDescription: "Codes of the Products to look up. Every Product is listed when not set.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"code_prefix": schema.StringAttribute{
+				MarkdownDescription: "Only list the Products whose code starts with this prefix. Conflicts with `codes`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("codes")),
+				},
+			},
+			"products": schema.ListNestedAttribute{
+				MarkdownDescription: "The Products found, ordered by code.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: productsEntryAttributes,
+				},
+			},
+			"ids": schema.MapAttribute{
+				MarkdownDescription: "The identifiers of the Products found, keyed by code, for use with `for_each`.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"extra_query": extraQueryAttribute,
+		},
+	}
+}
+
+// codes returns the codes of the Products to look up, or nil when every
+// Product is listed.
+func (r *ProductsDataSource) codes(ctx context.Context, data *ProductsDataSourceModel, diagnostics *diag.Diagnostics) []string {
+	if data.Codes.IsNull() {
+		return nil
+	}
+	var codes []string
+	diagnostics.Append(data.Codes.ElementsAs(ctx, &codes, false)...)
+	return codes
+}
+
+// done checks every code looked up was found, and maps the codes found to
+// the ids of the Products.
+func (r *ProductsDataSource) done(ctx context.Context, data *ProductsDataSourceModel, found []map[string]any, diagnostics *diag.Diagnostics) {
+	ids := make(map[string]attr.Value, len(found))
+	for _, restData := range found {
+		code, _ := restData["code"].(string)
+		id, _ := restData["id"].(string)
+		ids[code] = types.StringValue(id)
+	}
+
+	var missing []string
+	for _, code := range r.codes(ctx, data, diagnostics) {
+		if _, ok := ids[code]; !ok {
+			missing = append(missing, code)
+		}
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		diagnostics.AddError("Products not found", fmt.Sprintf("No products found with codes: %s", strings.Join(missing, ", ")))
+		return
+	}
+
+	mv, diag := types.MapValue(types.StringType, ids)
+	diagnostics.Append(diag...)
+	data.Ids = mv
+}
+
+func (r *ProductsDataSource) read(ctx context.Context, entry *productsEntryModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           restData,
+	}
+	m.to("id", &entry.Id)
+	m.to("name", &entry.Name)
+	m.to("code", &entry.Code)
+	m.to("version", &entry.Version)
+	entry.CustomFields = customFieldsJSON(restData, diagnostics)
+}
+
+// customFieldsJSON returns the custom fields of an entity as a JSON object,
+// for attributes which can't be dynamic.
+func customFieldsJSON(restData map[string]any, diagnostics *diag.Diagnostics) jsontypes.Normalized {
+	fields, _ := restData["customFields"].(map[string]any)
+	if fields == nil {
+		fields = map[string]any{}
+	}
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		diagnostics.AddError("Invalid custom fields", fmt.Sprintf("Unable to encode custom fields, got error: %s", err))
+		return jsontypes.NewNormalizedNull()
+	}
+	return jsontypes.NewNormalizedValue(string(encoded))
+}
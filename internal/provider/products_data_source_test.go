@@ -0,0 +1,92 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestProductsDataSourceList(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+	storageId := api.put("products", map[string]any{
+		"name":         "Storage",
+		"code":         "storage",
+		"customFields": map[string]any{"team": "infra", "priority": float64(2), "billable": true},
+	})
+	computeId := api.put("products", map[string]any{"name": "Compute", "code": "compute"})
+	api.put("products", map[string]any{"name": "Support", "code": "support"})
+
+	state := p.readDataSource("m3ter_products", map[string]any{})
+	var codes []any
+	for i := 0; i < attrValue(t, state, "products").(int); i++ {
+		codes = append(codes, attrValue(t, state, fmt.Sprintf("products.%d.code", i)))
+	}
+	if want := []any{"compute", "storage", "support"}; !reflect.DeepEqual(codes, want) {
+		t.Errorf("listed codes %v, want %v", codes, want)
+	}
+
+	// Custom fields keep the type of their values
+	var customFields map[string]any
+	if err := json.Unmarshal([]byte(attrValue(t, state, "products.1.custom_fields").(string)), &customFields); err != nil {
+		t.Fatalf("custom_fields is not JSON: %s", err)
+	}
+	if want := map[string]any{"team": "infra", "priority": float64(2), "billable": true}; !reflect.DeepEqual(customFields, want) {
+		t.Errorf("custom_fields = %v, want %v", customFields, want)
+	}
+	if v := attrValue(t, state, "products.0.custom_fields"); v != "{}" {
+		t.Errorf("custom_fields = %v without fields, want an empty object", v)
+	}
+	if v := attrValue(t, state, "ids.storage"); v != storageId {
+		t.Errorf("ids.storage = %v, want %s", v, storageId)
+	}
+
+	state = p.readDataSource("m3ter_products", map[string]any{"code_prefix": "s"})
+	if v := attrValue(t, state, "ids"); v != 2 || attrValue(t, state, "products.0.code") != "storage" {
+		t.Errorf("listed %v products with the prefix s, want storage and support", v)
+	}
+
+	state = p.readDataSource("m3ter_products", map[string]any{"codes": []any{"compute"}})
+	if v := attrValue(t, state, "ids"); v != 1 || attrValue(t, state, "ids.compute") != computeId {
+		t.Errorf("looked up %v products, want only compute", v)
+	}
+}
+
+func TestProductsDataSourceCodes(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+	storageId := api.put("products", map[string]any{"name": "Storage", "code": "storage"})
+	computeId := api.put("products", map[string]any{"name": "Compute", "code": "compute"})
+	api.put("products", map[string]any{"name": "Support", "code": "support"})
+
+	// The codes are looked up with the API's filter
+	state := p.readDataSource("m3ter_products", map[string]any{"codes": []any{"storage", "compute"}})
+	if v := attrValue(t, state, "ids"); v != 2 || attrValue(t, state, "ids.storage") != storageId || attrValue(t, state, "ids.compute") != computeId {
+		t.Errorf("looked up %v products, want storage and compute", v)
+	}
+	lists := api.requestsTo("GET", "/products")
+	if len(lists) != 1 {
+		t.Fatalf("listed products %d times, want once", len(lists))
+	}
+	codes := slices.Clone(lists[0].Query["codes"])
+	slices.Sort(codes)
+	if !reflect.DeepEqual(codes, []string{"compute", "storage"}) {
+		t.Errorf("listed products filtered by codes %v, want compute and storage", codes)
+	}
+
+	_, diags := p.tryReadDataSource("m3ter_products", map[string]any{"codes": []any{"storage", "missing"}})
+	if summary := diagnosticsSummary(diags); !hasErrors(diags) || !strings.Contains(summary, "missing") {
+		t.Errorf("got diagnostics %q, want the missing code reported", summary)
+	}
+
+	_, diags = p.tryReadDataSource("m3ter_products", map[string]any{"codes": []any{"storage"}, "code_prefix": "s"})
+	if !hasErrors(diags) {
+		t.Errorf("code_prefix was accepted with codes")
+	}
+}
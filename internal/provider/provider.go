@@ -5,15 +5,22 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 	"golang.org/x/time/rate"
@@ -33,9 +40,22 @@ type M3terProvider struct {
 
 // M3terProviderModel describes the provider data model.
 type M3terProviderModel struct {
-	OrganizationID types.String `tfsdk:"organization_id"`
-	AccessKey      types.String `tfsdk:"access_key"`
-	SecretKey      types.String `tfsdk:"secret_key"`
+	OrganizationID       types.String  `tfsdk:"organization_id"`
+	AccessKey            types.String  `tfsdk:"access_key"`
+	SecretKey            types.String  `tfsdk:"secret_key"`
+	RequiredCustomFields types.List    `tfsdk:"required_custom_fields"`
+	StrictMode           types.Bool    `tfsdk:"strict_mode"`
+	BaseURL              types.String  `tfsdk:"base_url"`
+	TokenURL             types.String  `tfsdk:"token_url"`
+	MaxRetries           types.Int64   `tfsdk:"max_retries"`
+	RetryBaseDelay       types.String  `tfsdk:"retry_base_delay"`
+	RequestTimeout       types.String  `tfsdk:"request_timeout"`
+	DisableListCache     types.Bool    `tfsdk:"disable_list_cache"`
+	ListCacheSize        types.Int64   `tfsdk:"list_cache_size"`
+	ReadRateLimit        types.Float64 `tfsdk:"read_rate_limit"`
+	WriteRateLimit       types.Float64 `tfsdk:"write_rate_limit"`
+	ProxyURL             types.String  `tfsdk:"proxy_url"`
+	CACertificate        types.String  `tfsdk:"ca_certificate"`
 }
 
 func (p *M3terProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -47,7 +67,7 @@ func (p *M3terProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"organization_id": schema.StringAttribute{
-				MarkdownDescription: "M3ter organization ID.",
+				MarkdownDescription: "M3ter organization ID. When neither this nor M3TER_ORGANIZATION_ID is set, the only organization the credentials have access to is used.",
 				Optional:            true,
 			},
 			"access_key": schema.StringAttribute{
@@ -59,6 +79,75 @@ func (p *M3terProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 				Optional:            true,
 				Sensitive:           true,
 			},
+			"required_custom_fields": schema.ListAttribute{
+				MarkdownDescription: "Custom field keys that must be set in the `custom_fields` of every resource that supports them. Planning fails for resources missing any of these keys.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"strict_mode": schema.BoolAttribute{
+				MarkdownDescription: "Fail when the API returns fields for a resource that the provider does not map, to detect fields added to the API which the provider should support. Such fields are only logged at debug level otherwise. Defaults to `false`.",
+				Optional:            true,
+			},
+			"base_url": schema.StringAttribute{
+				MarkdownDescription: "Base URL of the M3ter API, such as the one of the region the organization is hosted in. May also be set with M3TER_BASE_URL. Defaults to `" + defaultBaseURL + "`.",
+				Optional:            true,
+				Validators: []validator.String{
+					httpsURL(),
+				},
+			},
+			"token_url": schema.StringAttribute{
+				MarkdownDescription: "URL the OAuth access token is requested from. May also be set with M3TER_TOKEN_URL. Defaults to the `/oauth/token` path of the base URL.",
+				Optional:            true,
+				Validators: []validator.String{
+					httpsURL(),
+				},
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "URL of the proxy requests to the M3ter API, including those for OAuth access tokens, are sent through, such as `http://proxy.example.com:3128`. May include credentials, and use the `http`, `https` or `socks5` scheme. Defaults to the proxy set with the HTTPS_PROXY and NO_PROXY environment variables.",
+				Optional:            true,
+				Sensitive:           true,
+				Validators: []validator.String{
+					proxyURL(),
+				},
+			},
+			"ca_certificate": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded CA certificates, or the path of a file holding them, trusted in addition to the system ones when connecting to the M3ter API, the OAuth token URL and an https proxy. Required when a TLS-inspecting proxy signs certificates with an internal CA.",
+				Optional:            true,
+			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("How many times a request is retried when rate limited (429) or, for requests which are safe to repeat, on a server error (5xx). Defaults to `%d`.", defaultMaxRetries),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"request_timeout": schema.StringAttribute{
+				MarkdownDescription: "How long a single request to the M3ter API may take, such as `30s` or `2m`, before it is abandoned. Every retry of a request gets the full timeout again. Defaults to `1m`.",
+				Optional:            true,
+			},
+			"disable_list_cache": schema.BoolAttribute{
+				MarkdownDescription: "Disable caching the entities listed by data sources looking up a single entity, so every data source lists the entities itself. The cache lets many data sources looking up entities of the same type list them once, and is cleared by every write. Defaults to `false`.",
+				Optional:            true,
+			},
+			"list_cache_size": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("How many lists of entities are cached at most, evicting the oldest first. Defaults to `%d`.", defaultListCacheSize),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"read_rate_limit": schema.Float64Attribute{
+				MarkdownDescription: fmt.Sprintf("How many read requests are sent to the M3ter API per second at most. Defaults to `%d`.", defaultRateLimit),
+				Optional:            true,
+			},
+			"write_rate_limit": schema.Float64Attribute{
+				MarkdownDescription: fmt.Sprintf("How many write requests are sent to the M3ter API per second at most, in addition to the read requests. Defaults to `%d`.", defaultRateLimit),
+				Optional:            true,
+			},
+			"retry_base_delay": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("Delay before the first retry of a request, such as `500ms` or `2s`, doubled for every further retry and randomized to spread out concurrent retries. A `Retry-After` header in the response, in seconds or as a date, takes precedence. Defaults to `%s`.", defaultRetryBaseDelay),
+				Optional:            true,
+			},
 		},
 	}
 }
@@ -99,6 +188,42 @@ func (p *M3terProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		)
 	}
 
+	if data.BaseURL.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("base_url"),
+			"Unknown M3ter Base URL",
+			"The provider cannot create the M3ter API client as there is an unknown configuration value for the M3ter Base URL. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the M3TER_BASE_URL environment variable.",
+		)
+	}
+
+	if data.TokenURL.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("token_url"),
+			"Unknown M3ter Token URL",
+			"The provider cannot create the M3ter API client as there is an unknown configuration value for the M3ter Token URL. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the M3TER_TOKEN_URL environment variable.",
+		)
+	}
+
+	if data.ProxyURL.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("proxy_url"),
+			"Unknown M3ter Proxy URL",
+			"The provider cannot create the M3ter API client as there is an unknown configuration value for the M3ter Proxy URL. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the HTTPS_PROXY environment variable.",
+		)
+	}
+
+	if data.CACertificate.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ca_certificate"),
+			"Unknown M3ter CA Certificate",
+			"The provider cannot create the M3ter API client as there is an unknown configuration value for the M3ter CA Certificate. "+
+				"Either target apply the source of the value first, or set the value statically in the configuration.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -106,6 +231,8 @@ func (p *M3terProvider) Configure(ctx context.Context, req provider.ConfigureReq
 	organizationID := os.Getenv("M3TER_ORGANIZATION_ID")
 	accessKey := os.Getenv("M3TER_ACCESS_KEY")
 	secretKey := os.Getenv("M3TER_SECRET_KEY")
+	baseURL := os.Getenv("M3TER_BASE_URL")
+	tokenURL := os.Getenv("M3TER_TOKEN_URL")
 
 	if !data.OrganizationID.IsNull() {
 		organizationID = data.OrganizationID.ValueString()
@@ -119,12 +246,39 @@ func (p *M3terProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		secretKey = data.SecretKey.ValueString()
 	}
 
-	if organizationID == "" {
+	if !data.BaseURL.IsNull() {
+		baseURL = data.BaseURL.ValueString()
+	}
+
+	if !data.TokenURL.IsNull() {
+		tokenURL = data.TokenURL.ValueString()
+	}
+
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	if tokenURL == "" {
+		tokenURL = baseURL + "/oauth/token"
+	}
+
+	// The environment variables aren't checked by the schema validators
+	if err := checkHTTPSURL(baseURL); err != nil {
 		resp.Diagnostics.AddAttributeError(
-			path.Root("organization_id"),
-			"Missing M3ter Organization ID",
-			"The provider cannot create the M3ter API client as there is no configuration value for the M3ter Organization ID. "+
-				"Set the value statically in the configuration or use the M3TER_ORGANIZATION_ID environment variable.",
+			path.Root("base_url"),
+			"Invalid M3ter Base URL",
+			"The provider cannot create the M3ter API client as the M3ter Base URL is invalid: "+err.Error()+". "+
+				"Set an absolute https URL in the configuration or the M3TER_BASE_URL environment variable.",
+		)
+	}
+
+	if err := checkHTTPSURL(tokenURL); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("token_url"),
+			"Invalid M3ter Token URL",
+			"The provider cannot create the M3ter API client as the M3ter Token URL is invalid: "+err.Error()+". "+
+				"Set an absolute https URL in the configuration or the M3TER_TOKEN_URL environment variable.",
 		)
 	}
 
@@ -153,19 +307,160 @@ func (p *M3terProvider) Configure(ctx context.Context, req provider.ConfigureReq
 	cnf := clientcredentials.Config{
 		ClientID:     accessKey,
 		ClientSecret: secretKey,
-		TokenURL:     "https://api.m3ter.com/oauth/token",
+		TokenURL:     tokenURL,
 		AuthStyle:    oauth2.AuthStyleInHeader,
 	}
 
-	client := &m3terClient{
-		organizationID: organizationID,
-		client:         cnf.Client(context.Background()),
-		limit:          rate.NewLimiter(rate.Limit(10), 1),
+	readRateLimit := rateLimitSetting(data.ReadRateLimit, path.Root("read_rate_limit"), &resp.Diagnostics)
+	writeRateLimit := rateLimitSetting(data.WriteRateLimit, path.Root("write_rate_limit"), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := newM3terClient(baseURL, organizationID, &cnf, rate.NewLimiter(readRateLimit, 1), rate.NewLimiter(writeRateLimit, 1))
+	client.userAgent = "terraform-provider-m3ter/" + p.version + " (+https://github.com/housecanary/terraform-provider-m3ter)"
+	proxySource := "environment"
+	if !data.ProxyURL.IsNull() {
+		proxySource = "config"
+		proxyURL, err := parseProxyURL(data.ProxyURL.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("proxy_url"),
+				"Invalid M3ter Proxy URL",
+				"The provider cannot create the M3ter API client as the M3ter Proxy URL is invalid: "+err.Error()+".",
+			)
+			return
+		}
+		client.setProxy(proxyURL)
+	}
+	if !data.CACertificate.IsNull() {
+		pool, err := loadCACertificates(data.CACertificate.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ca_certificate"),
+				"Invalid M3ter CA Certificate",
+				"The provider cannot create the M3ter API client as the M3ter CA Certificate could not be loaded: "+err.Error()+". "+
+					"Set PEM encoded certificates, or the path of a file holding them.",
+			)
+			return
+		}
+		client.setRootCAs(pool)
+	}
+	if !data.MaxRetries.IsNull() && !data.MaxRetries.IsUnknown() {
+		client.maxRetries = int(data.MaxRetries.ValueInt64())
+	}
+	if !data.RetryBaseDelay.IsNull() && !data.RetryBaseDelay.IsUnknown() {
+		retryBaseDelay, err := time.ParseDuration(data.RetryBaseDelay.ValueString())
+		if err != nil || retryBaseDelay <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("retry_base_delay"),
+				"Invalid Retry Base Delay",
+				fmt.Sprintf("The retry base delay must be a positive duration such as 500ms or 2s, got %q.", data.RetryBaseDelay.ValueString()),
+			)
+			return
+		}
+		client.retryBaseDelay = retryBaseDelay
+	}
+	if !data.RequestTimeout.IsNull() && !data.RequestTimeout.IsUnknown() {
+		requestTimeout, err := time.ParseDuration(data.RequestTimeout.ValueString())
+		if err != nil || requestTimeout <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("request_timeout"),
+				"Invalid Request Timeout",
+				fmt.Sprintf("The request timeout must be a positive duration such as 30s or 2m, got %q.", data.RequestTimeout.ValueString()),
+			)
+			return
+		}
+		client.requestTimeout = requestTimeout
+	}
+	if data.DisableListCache.ValueBool() {
+		client.listCache = nil
+	} else if !data.ListCacheSize.IsNull() && !data.ListCacheSize.IsUnknown() {
+		client.listCache = newListCache(int(data.ListCacheSize.ValueInt64()))
+	}
+	organizationIDSource := settingSource(data.OrganizationID, "M3TER_ORGANIZATION_ID")
+	if organizationID == "" {
+		organizationIDSource = "discovered"
+		// Without an organization ID, use the only organization the
+		// credentials have access to
+		discoveredID, err := client.discoverOrganizationID(ctx)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("organization_id"),
+				"Missing M3ter Organization ID",
+				"The provider cannot create the M3ter API client as there is no configuration value for the M3ter Organization ID, and it could not be discovered: "+err.Error()+". "+
+					"Set the value statically in the configuration or use the M3TER_ORGANIZATION_ID environment variable.",
+			)
+			return
+		}
+		client.organizationID = discoveredID
+	}
+	if !data.RequiredCustomFields.IsNull() && !data.RequiredCustomFields.IsUnknown() {
+		resp.Diagnostics.Append(data.RequiredCustomFields.ElementsAs(ctx, &client.requiredCustomFields, false)...)
+	}
+	client.strictMode = data.StrictMode.ValueBool()
+
+	// Log the effective settings and where they came from, to help diagnose
+	// setups behaving differently between environments. Secrets are never
+	// logged, only their source.
+	listCacheSize := 0
+	if client.listCache != nil {
+		listCacheSize = client.listCache.maxEntries
 	}
+	tflog.Debug(ctx, "Configured m3ter provider", map[string]any{
+		"version":                p.version,
+		"base_url":               client.baseURL,
+		"base_url_source":        settingSource(data.BaseURL, "M3TER_BASE_URL"),
+		"token_url":              tokenURL,
+		"token_url_source":       settingSource(data.TokenURL, "M3TER_TOKEN_URL"),
+		"organization_id":        client.organizationID,
+		"organization_id_source": organizationIDSource,
+		"access_key_source":      settingSource(data.AccessKey, "M3TER_ACCESS_KEY"),
+		"secret_key_source":      settingSource(data.SecretKey, "M3TER_SECRET_KEY"),
+		"proxy_source":           proxySource,
+		"custom_ca_certificate":  !data.CACertificate.IsNull(),
+		"read_rate_limit":        float64(client.readLimit.Limit()),
+		"write_rate_limit":       float64(client.writeLimit.Limit()),
+		"max_retries":            client.maxRetries,
+		"retry_base_delay":       client.retryBaseDelay.String(),
+		"request_timeout":        client.requestTimeout.String(),
+		"list_cache_size":        listCacheSize,
+		"strict_mode":            client.strictMode,
+	})
+
 	resp.DataSourceData = client
 	resp.ResourceData = client
 }
 
+// rateLimitSetting returns the configured rate limit, or defaultRateLimit
+// when it's not set.
+func rateLimitSetting(configured types.Float64, attributePath path.Path, diagnostics *diag.Diagnostics) rate.Limit {
+	if configured.IsNull() || configured.IsUnknown() {
+		return rate.Limit(defaultRateLimit)
+	}
+	if configured.ValueFloat64() <= 0 {
+		diagnostics.AddAttributeError(
+			attributePath,
+			"Invalid Rate Limit",
+			fmt.Sprintf("The rate limit must be a positive number of requests per second, got %g.", configured.ValueFloat64()),
+		)
+	}
+	return rate.Limit(configured.ValueFloat64())
+}
+
+// settingSource describes where the value of a provider setting was taken
+// from: the configuration, the given environment variable, or its default.
+func settingSource(configured types.String, env string) string {
+	switch {
+	case !configured.IsNull():
+		return "config"
+	case os.Getenv(env) != "":
+		return "environment"
+	default:
+		return "default"
+	}
+}
+
 func (p *M3terProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewIntegrationConfigurationResource,
@@ -173,22 +468,42 @@ func (p *M3terProvider) Resources(ctx context.Context) []func() resource.Resourc
 		NewScheduledEventConfigurationResource,
 		NewWebhookDestinationResource,
 		NewOrganizationConfigResource,
+		NewOrganizationCustomFieldsResource,
 		NewProductResource,
 		NewPricingResource,
 		NewPlanTemplateResource,
 		NewPlanResource,
 		NewPlanGroupResource,
 		NewPlanGroupLinkResource,
+		NewPlanGroupLinksResource,
 		NewAggregationResource,
 		NewMeterResource,
 		NewCounterResource,
+		NewAccountResource,
+		NewCommitmentResource,
+		NewBalanceResource,
+		NewAccountPlanResource,
+		NewServiceUserResource,
+		NewStatementDefinitionResource,
+		NewCreditReasonResource,
+		NewCurrencyResource,
 	}
 }
 
 func (p *M3terProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewProductDataSource,
+		NewProductsDataSource,
+		NewAccountDataSource,
+		NewPlanGroupLinksDataSource,
 		NewAggregationDataSource,
+		NewMeterDataSource,
+		NewMetersDataSource,
+		NewPlanDataSource,
+		NewPlansDataSource,
+		NewPlanTemplateDataSource,
+		NewPricingDataSource,
+		NewOrganizationConfigDataSource,
 	}
 }
 
@@ -0,0 +1,1085 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"maps"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testOrganizationID is the organization the fake API serves.
+const testOrganizationID = "00000000-0000-4000-8000-000000000001"
+
+// fakeRequest is a request received by the fake API.
+type fakeRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   map[string]any
+}
+
+// fakeHandler handles a request to the fake API instead of the in-memory
+// store, returning false to let the store handle it.
+type fakeHandler func(w http.ResponseWriter, req *fakeRequest) bool
+
+// fakeAPI is an in-memory m3ter API serving the organization's entities over
+// https. Entities are stored per collection, such as "meters", and get an ID
+// and a version bumped on every update like the real API.
+type fakeAPI struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu         sync.Mutex
+	entities   map[string]map[string]map[string]any
+	order      map[string][]string
+	singletons map[string]map[string]any
+	nextID     int
+	requests   []*fakeRequest
+	handlers   []fakeHandler
+
+	// pageSize, when set, overrides the page size requested by list calls
+	pageSize int
+	// onWrite, when set, is called with the entities stored by creates and
+	// updates, to emulate server side defaults
+	onWrite func(collection string, entity map[string]any)
+}
+
+// fakeSingletons are the collections holding a single entity rather than a
+// list of them.
+var fakeSingletons = []string{"organizationconfig", "customfields"}
+
+// fakeParents are the entities whose writes bump the version of the entity
+// they depend on, keyed by collection, with the field referencing the parent.
+var fakeParents = map[string]struct{ field, collection string }{
+	"aggregations": {"meterId", "meters"},
+	"pricings":     {"aggregationId", "aggregations"},
+}
+
+// fakeNestedCollections are the collections whose path has two segments, such
+// as "integrationdestinations/webhooks".
+var fakeNestedCollections = []string{"integrationdestinations/webhooks", "notifications/configurations", "scheduledevents/configurations", "picklists/currency", "picklists/creditreasons"}
+
+func newFakeAPI(t *testing.T) *fakeAPI {
+	t.Helper()
+
+	api := &fakeAPI{
+		t:          t,
+		entities:   make(map[string]map[string]map[string]any),
+		order:      make(map[string][]string),
+		singletons: make(map[string]map[string]any),
+	}
+	api.server = httptest.NewTLSServer(http.HandlerFunc(api.serveHTTP))
+	t.Cleanup(api.server.Close)
+	return api
+}
+
+// handle registers a handler consulted before the store, most recently
+// registered first.
+func (a *fakeAPI) handle(h fakeHandler) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.handlers = append([]fakeHandler{h}, a.handlers...)
+}
+
+// put stores an entity in a collection, assigning it an ID and version if it
+// has none, and returns its ID.
+func (a *fakeAPI) put(collection string, entity map[string]any) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if slices.Contains(fakeSingletons, collection) {
+		a.singletons[collection] = entity
+		return ""
+	}
+
+	id, _ := entity["id"].(string)
+	if id == "" {
+		id = a.newID()
+		entity["id"] = id
+	}
+	if _, ok := entity["version"]; !ok {
+		entity["version"] = float64(1)
+	}
+	if a.entities[collection] == nil {
+		a.entities[collection] = make(map[string]map[string]any)
+	}
+	if _, ok := a.entities[collection][id]; !ok {
+		a.order[collection] = append(a.order[collection], id)
+	}
+	a.entities[collection][id] = entity
+	return id
+}
+
+// get returns the entity with the given ID in a collection, or the singleton
+// when id is empty.
+func (a *fakeAPI) get(collection, id string) map[string]any {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if id == "" {
+		return a.singletons[collection]
+	}
+	return a.entities[collection][id]
+}
+
+// requestsTo returns the requests received with the given method for paths
+// starting with the given organization scoped prefix.
+func (a *fakeAPI) requestsTo(method, prefix string) []*fakeRequest {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var found []*fakeRequest
+	for _, req := range a.requests {
+		if req.Method == method && strings.HasPrefix(req.Path, prefix) {
+			found = append(found, req)
+		}
+	}
+	return found
+}
+
+// sentRequests returns the method and path of every request received.
+func (a *fakeAPI) sentRequests() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var sent []string
+	for _, req := range a.requests {
+		sent = append(sent, req.Method+" "+req.Path)
+	}
+	return sent
+}
+
+// clearRequests forgets the requests received so far.
+func (a *fakeAPI) clearRequests() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.requests = nil
+}
+
+func (a *fakeAPI) newID() string {
+	a.nextID++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", a.nextID+1000)
+}
+
+func (a *fakeAPI) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/oauth/token" {
+		writeJSON(w, http.StatusOK, map[string]any{"access_token": "token", "token_type": "bearer", "expires_in": 3600})
+		return
+	}
+
+	prefix := "/organizations/" + testOrganizationID
+	if !strings.HasPrefix(r.URL.Path, prefix+"/") {
+		writeJSON(w, http.StatusNotFound, map[string]any{"message": "not found"})
+		return
+	}
+
+	req := &fakeRequest{
+		Method: r.Method,
+		Path:   strings.TrimPrefix(r.URL.Path, prefix),
+		Query:  r.URL.Query(),
+		Header: r.Header,
+	}
+	if body, _ := io.ReadAll(r.Body); len(body) > 0 {
+		if err := json.Unmarshal(body, &req.Body); err != nil {
+			a.t.Errorf("fake API: invalid request body for %s %s: %s", r.Method, r.URL.Path, err)
+		}
+	}
+
+	a.mu.Lock()
+	a.requests = append(a.requests, req)
+	handlers := slices.Clone(a.handlers)
+	a.mu.Unlock()
+
+	for _, h := range handlers {
+		if h(w, req) {
+			return
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	status, body := a.serveStore(req)
+	writeJSON(w, status, body)
+}
+
+func (a *fakeAPI) serveStore(req *fakeRequest) (int, any) {
+	segments := strings.Split(strings.TrimPrefix(req.Path, "/"), "/")
+	if len(segments) > 1 && slices.Contains(fakeNestedCollections, segments[0]+"/"+segments[1]) {
+		segments = append([]string{segments[0] + "/" + segments[1]}, segments[2:]...)
+	}
+	collection := segments[0]
+
+	if slices.Contains(fakeSingletons, collection) {
+		switch req.Method {
+		case http.MethodGet:
+			if a.singletons[collection] == nil {
+				return http.StatusOK, map[string]any{}
+			}
+			return http.StatusOK, a.singletons[collection]
+		case http.MethodPut:
+			current := a.singletons[collection]
+			if status, body := checkVersion(current, req.Body); status != 0 {
+				return status, body
+			}
+			entity := maps.Clone(req.Body)
+			entity["version"] = nextVersion(current)
+			a.write(collection, entity)
+			a.singletons[collection] = entity
+			return http.StatusOK, entity
+		}
+		return http.StatusMethodNotAllowed, map[string]any{"message": "method not allowed"}
+	}
+
+	if len(segments) == 1 {
+		switch req.Method {
+		case http.MethodGet:
+			return a.list(collection, req.Query)
+		case http.MethodPost:
+			entity := maps.Clone(req.Body)
+			entity["id"] = a.newID()
+			entity["version"] = float64(1)
+			a.write(collection, entity)
+			if a.entities[collection] == nil {
+				a.entities[collection] = make(map[string]map[string]any)
+			}
+			a.entities[collection][entity["id"].(string)] = entity
+			a.order[collection] = append(a.order[collection], entity["id"].(string))
+			return http.StatusOK, entity
+		}
+		return http.StatusMethodNotAllowed, map[string]any{"message": "method not allowed"}
+	}
+
+	id := segments[1]
+	current, ok := a.entities[collection][id]
+	if !ok || len(segments) > 2 {
+		return http.StatusNotFound, map[string]any{"message": "not found"}
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		return http.StatusOK, current
+	case http.MethodPut:
+		if status, body := checkVersion(current, req.Body); status != 0 {
+			return status, body
+		}
+		entity := maps.Clone(req.Body)
+		entity["id"] = id
+		entity["version"] = nextVersion(current)
+		a.write(collection, entity)
+		a.entities[collection][id] = entity
+		return http.StatusOK, entity
+	case http.MethodDelete:
+		delete(a.entities[collection], id)
+		a.order[collection] = slices.DeleteFunc(a.order[collection], func(s string) bool { return s == id })
+		return http.StatusOK, current
+	}
+	return http.StatusMethodNotAllowed, map[string]any{"message": "method not allowed"}
+}
+
+func (a *fakeAPI) write(collection string, entity map[string]any) {
+	// Pricing bands get IDs from the API
+	for _, key := range []string{"pricingBands", "overagePricingBands"} {
+		bands, _ := entity[key].([]any)
+		for _, band := range bands {
+			if band, ok := band.(map[string]any); ok && band["id"] == nil {
+				band["id"] = a.newID()
+			}
+		}
+	}
+	if a.onWrite != nil {
+		a.onWrite(collection, entity)
+	}
+	// Writing an entity bumps the version of the entity it depends on, like
+	// the cascades of the real API
+	if parent, ok := fakeParents[collection]; ok {
+		id, _ := entity[parent.field].(string)
+		if current := a.entities[parent.collection][id]; current != nil {
+			current["version"] = nextVersion(current)
+		}
+	}
+}
+
+// list serves a page of a collection, filtered by the query parameters which
+// match entity fields, and by codes.
+func (a *fakeAPI) list(collection string, query url.Values) (int, any) {
+	var matches []map[string]any
+	for _, id := range a.order[collection] {
+		entity := a.entities[collection][id]
+		match := true
+		for key, values := range query {
+			switch key {
+			case "pageSize", "nextToken":
+			case "codes":
+				code, _ := entity["code"].(string)
+				match = match && slices.Contains(values, code)
+			case "ids":
+				match = match && slices.Contains(values, id)
+			default:
+				if v, ok := entity[key]; ok {
+					s, _ := v.(string)
+					match = match && s == values[0]
+				}
+			}
+		}
+		if match {
+			matches = append(matches, entity)
+		}
+	}
+
+	pageSize, _ := strconv.Atoi(query.Get("pageSize"))
+	if a.pageSize > 0 {
+		pageSize = a.pageSize
+	}
+	if pageSize <= 0 {
+		pageSize = len(matches)
+	}
+	start, _ := strconv.Atoi(query.Get("nextToken"))
+	end := min(start+pageSize, len(matches))
+	response := map[string]any{"data": append([]map[string]any{}, matches[min(start, end):end]...)}
+	if end < len(matches) {
+		response["nextToken"] = strconv.Itoa(end)
+	}
+	return http.StatusOK, response
+}
+
+// checkVersion returns a 409 response when the version sent doesn't match
+// the current one.
+func checkVersion(current, body map[string]any) (int, any) {
+	sent, ok := body["version"]
+	if !ok || current == nil {
+		return 0, nil
+	}
+	if sent != current["version"] {
+		return http.StatusConflict, map[string]any{"message": fmt.Sprintf("version %v does not match current version %v", sent, current["version"])}
+	}
+	return 0, nil
+}
+
+func nextVersion(current map[string]any) float64 {
+	version, _ := current["version"].(float64)
+	return version + 1
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if body != nil {
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
+
+// unknown is a configuration value which isn't known until apply.
+var unknown = &struct{ unknown bool }{true}
+
+// testProvider drives the provider through the protocol server like
+// Terraform does, against a fake API.
+type testProvider struct {
+	t      *testing.T
+	ctx    context.Context
+	api    *fakeAPI
+	server tfprotov6.ProviderServer
+	schema *tfprotov6.GetProviderSchemaResponse
+}
+
+// newTestProvider configures the provider against the fake API, with the
+// given provider configuration overrides.
+func newTestProvider(t *testing.T, api *fakeAPI, config map[string]any) *testProvider {
+	t.Helper()
+
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &testProvider{t: t, ctx: context.Background(), api: api, server: server}
+
+	p.schema, err = server.GetProviderSchema(p.ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.checkDiagnostics("provider schema", p.schema.Diagnostics)
+
+	providerConfig := map[string]any{
+		"organization_id":  testOrganizationID,
+		"access_key":       "access",
+		"secret_key":       "secret",
+		"base_url":         api.server.URL,
+		"ca_certificate":   string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: api.server.Certificate().Raw})),
+		"retry_base_delay": "1ms",
+	}
+	for k, v := range config {
+		providerConfig[k] = v
+	}
+	configValue := p.value(p.schema.Provider.ValueType(), providerConfig)
+	resp, err := server.ConfigureProvider(p.ctx, &tfprotov6.ConfigureProviderRequest{
+		TerraformVersion: "1.9.0",
+		Config:           p.dynamicValue(configValue),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.checkDiagnostics("configure provider", resp.Diagnostics)
+	return p
+}
+
+// resourceSchema returns the schema of a resource type, such as "m3ter_meter".
+func (p *testProvider) resourceSchema(typeName string) *tfprotov6.Schema {
+	p.t.Helper()
+
+	s, ok := p.schema.ResourceSchemas[typeName]
+	if !ok {
+		p.t.Fatalf("unknown resource type %s", typeName)
+	}
+	return s
+}
+
+// create plans and applies the creation of a resource, returning its state.
+func (p *testProvider) create(typeName string, config map[string]any) tftypes.Value {
+	p.t.Helper()
+
+	planned, diags := p.plan(typeName, p.null(typeName), config)
+	p.checkDiagnostics("plan create "+typeName, diags)
+	state, diags := p.apply(typeName, p.null(typeName), planned, config)
+	p.checkDiagnostics("create "+typeName, diags)
+	return state
+}
+
+// update plans and applies a change of configuration, returning the new
+// state.
+func (p *testProvider) update(typeName string, prior tftypes.Value, config map[string]any) tftypes.Value {
+	p.t.Helper()
+
+	planned, diags := p.plan(typeName, prior, config)
+	p.checkDiagnostics("plan update "+typeName, diags)
+	state, diags := p.apply(typeName, prior, planned, config)
+	p.checkDiagnostics("update "+typeName, diags)
+	return state
+}
+
+// destroy plans and applies the deletion of a resource.
+func (p *testProvider) destroy(typeName string, prior tftypes.Value) []*tfprotov6.Diagnostic {
+	p.t.Helper()
+
+	null := p.null(typeName)
+	resp, err := p.server.PlanResourceChange(p.ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       p.dynamicValue(prior),
+		ProposedNewState: p.dynamicValue(null),
+		Config:           p.dynamicValue(null),
+	})
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	if hasErrors(resp.Diagnostics) {
+		return resp.Diagnostics
+	}
+	applyResp, err := p.server.ApplyResourceChange(p.ctx, &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:     typeName,
+		PriorState:   p.dynamicValue(prior),
+		PlannedState: p.dynamicValue(null),
+		Config:       p.dynamicValue(null),
+	})
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	return applyResp.Diagnostics
+}
+
+// read refreshes the state of a resource.
+func (p *testProvider) read(typeName string, state tftypes.Value) tftypes.Value {
+	p.t.Helper()
+
+	resp, err := p.server.ReadResource(p.ctx, &tfprotov6.ReadResourceRequest{
+		TypeName:     typeName,
+		CurrentState: p.dynamicValue(state),
+	})
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	p.checkDiagnostics("read "+typeName, resp.Diagnostics)
+	return p.fromDynamicValue(p.resourceSchema(typeName).ValueType(), resp.NewState)
+}
+
+// importState imports a resource by ID and reads it, like terraform import.
+func (p *testProvider) importState(typeName, id string) tftypes.Value {
+	p.t.Helper()
+
+	state, diags := p.tryImportState(typeName, id)
+	p.checkDiagnostics("import "+typeName, diags)
+	return p.read(typeName, state)
+}
+
+// tryImportState imports a resource by ID without reading it.
+func (p *testProvider) tryImportState(typeName, id string) (tftypes.Value, []*tfprotov6.Diagnostic) {
+	p.t.Helper()
+
+	resp, err := p.server.ImportResourceState(p.ctx, &tfprotov6.ImportResourceStateRequest{
+		TypeName: typeName,
+		ID:       id,
+	})
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	if hasErrors(resp.Diagnostics) {
+		return tftypes.Value{}, resp.Diagnostics
+	}
+	if len(resp.ImportedResources) != 1 {
+		p.t.Fatalf("import %s: got %d resources", typeName, len(resp.ImportedResources))
+	}
+	return p.fromDynamicValue(p.resourceSchema(typeName).ValueType(), resp.ImportedResources[0].State), resp.Diagnostics
+}
+
+// assertNoChanges fails the test when planning the configuration against the
+// state would change anything.
+func (p *testProvider) assertNoChanges(typeName string, state tftypes.Value, config map[string]any) {
+	p.t.Helper()
+
+	planned, diags := p.plan(typeName, state, config)
+	p.checkDiagnostics("plan "+typeName, diags)
+	if !planned.Equal(state) {
+		diffs, _ := state.Diff(planned)
+		var changes []string
+		for _, d := range diffs {
+			changes = append(changes, fmt.Sprintf("%s: %s => %s", d.Path, d.Value1, d.Value2))
+		}
+		p.t.Errorf("plan %s: expected no changes, got:\n%s", typeName, strings.Join(changes, "\n"))
+	}
+}
+
+// plan plans a change from the prior state to the configuration, checking
+// the planned values like Terraform does.
+func (p *testProvider) plan(typeName string, prior tftypes.Value, config map[string]any) (tftypes.Value, []*tfprotov6.Diagnostic) {
+	p.t.Helper()
+
+	s := p.resourceSchema(typeName)
+	configValue := p.value(s.ValueType(), config)
+
+	validateResp, err := p.server.ValidateResourceConfig(p.ctx, &tfprotov6.ValidateResourceConfigRequest{
+		TypeName: typeName,
+		Config:   p.dynamicValue(configValue),
+	})
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	if hasErrors(validateResp.Diagnostics) {
+		return tftypes.Value{}, validateResp.Diagnostics
+	}
+
+	proposed := proposedNewState(s.Block.Attributes, prior, configValue)
+	resp, err := p.server.PlanResourceChange(p.ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       p.dynamicValue(prior),
+		ProposedNewState: p.dynamicValue(proposed),
+		Config:           p.dynamicValue(configValue),
+	})
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	diags := append(validateResp.Diagnostics, resp.Diagnostics...)
+	if hasErrors(diags) {
+		return tftypes.Value{}, diags
+	}
+
+	planned := p.fromDynamicValue(s.ValueType(), resp.PlannedState)
+	if err := assertPlanValid(tftypes.NewAttributePath(), s.Block.Attributes, configValue, planned); err != nil {
+		p.t.Fatalf("plan %s: provider produced invalid plan: %s", typeName, err)
+	}
+	return planned, diags
+}
+
+// apply applies a planned change, checking the new state is consistent with
+// the plan like Terraform does.
+func (p *testProvider) apply(typeName string, prior, planned tftypes.Value, config map[string]any) (tftypes.Value, []*tfprotov6.Diagnostic) {
+	p.t.Helper()
+
+	s := p.resourceSchema(typeName)
+	resp, err := p.server.ApplyResourceChange(p.ctx, &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:     typeName,
+		PriorState:   p.dynamicValue(prior),
+		PlannedState: p.dynamicValue(planned),
+		Config:       p.dynamicValue(p.value(s.ValueType(), config)),
+	})
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	if hasErrors(resp.Diagnostics) {
+		return tftypes.Value{}, resp.Diagnostics
+	}
+
+	state := p.fromDynamicValue(s.ValueType(), resp.NewState)
+	if err := assertCompatible(tftypes.NewAttributePath(), planned, state); err != nil {
+		p.t.Fatalf("apply %s: provider produced inconsistent result after apply: %s", typeName, err)
+	}
+	return state, resp.Diagnostics
+}
+
+// readDataSource reads a data source with the given configuration.
+func (p *testProvider) readDataSource(typeName string, config map[string]any) tftypes.Value {
+	p.t.Helper()
+
+	state, diags := p.tryReadDataSource(typeName, config)
+	p.checkDiagnostics("read data source "+typeName, diags)
+	return state
+}
+
+// tryReadDataSource validates the configuration of a data source and reads
+// it, returning the diagnostics of both instead of failing on errors.
+func (p *testProvider) tryReadDataSource(typeName string, config map[string]any) (tftypes.Value, []*tfprotov6.Diagnostic) {
+	p.t.Helper()
+
+	s, ok := p.schema.DataSourceSchemas[typeName]
+	if !ok {
+		p.t.Fatalf("unknown data source type %s", typeName)
+	}
+	configValue := p.value(s.ValueType(), config)
+	validateResp, err := p.server.ValidateDataResourceConfig(p.ctx, &tfprotov6.ValidateDataResourceConfigRequest{
+		TypeName: typeName,
+		Config:   p.dynamicValue(configValue),
+	})
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	if hasErrors(validateResp.Diagnostics) {
+		return tftypes.NewValue(s.ValueType(), nil), validateResp.Diagnostics
+	}
+	resp, err := p.server.ReadDataSource(p.ctx, &tfprotov6.ReadDataSourceRequest{
+		TypeName: typeName,
+		Config:   p.dynamicValue(configValue),
+	})
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	return p.fromDynamicValue(s.ValueType(), resp.State), resp.Diagnostics
+}
+
+func (p *testProvider) null(typeName string) tftypes.Value {
+	return tftypes.NewValue(p.resourceSchema(typeName).ValueType(), nil)
+}
+
+func (p *testProvider) dynamicValue(v tftypes.Value) *tfprotov6.DynamicValue {
+	p.t.Helper()
+
+	dv, err := tfprotov6.NewDynamicValue(v.Type(), v)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	return &dv
+}
+
+func (p *testProvider) fromDynamicValue(typ tftypes.Type, dv *tfprotov6.DynamicValue) tftypes.Value {
+	p.t.Helper()
+
+	v, err := dv.Unmarshal(typ)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	return v
+}
+
+// value converts Go values into a value of the given type: strings, numbers,
+// booleans, []any for lists and sets, and map[string]any for maps and
+// objects, whose missing attributes are null.
+func (p *testProvider) value(typ tftypes.Type, v any) tftypes.Value {
+	p.t.Helper()
+
+	value, err := toValue(typ, v)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	return value
+}
+
+func toValue(typ tftypes.Type, v any) (tftypes.Value, error) {
+	if v == nil {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	if v == unknown {
+		return tftypes.NewValue(typ, tftypes.UnknownValue), nil
+	}
+	if typ.Is(tftypes.DynamicPseudoType) {
+		typ = inferType(v)
+	}
+
+	switch {
+	case typ.Is(tftypes.String):
+		return tftypes.NewValue(typ, v), nil
+	case typ.Is(tftypes.Bool):
+		return tftypes.NewValue(typ, v), nil
+	case typ.Is(tftypes.Number):
+		switch n := v.(type) {
+		case int:
+			return tftypes.NewValue(typ, big.NewFloat(float64(n))), nil
+		case float64:
+			return tftypes.NewValue(typ, big.NewFloat(n)), nil
+		case string:
+			f, _, err := big.ParseFloat(n, 10, 512, big.ToNearestEven)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			return tftypes.NewValue(typ, f), nil
+		}
+		return tftypes.NewValue(typ, v), nil
+	case typ.Is(tftypes.List{}), typ.Is(tftypes.Set{}):
+		var elemType tftypes.Type
+		if l, ok := typ.(tftypes.List); ok {
+			elemType = l.ElementType
+		} else {
+			elemType = typ.(tftypes.Set).ElementType
+		}
+		var elems []tftypes.Value
+		for _, e := range v.([]any) {
+			ev, err := toValue(elemType, e)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems = append(elems, ev)
+		}
+		return tftypes.NewValue(typ, elems), nil
+	case typ.Is(tftypes.Tuple{}):
+		t := typ.(tftypes.Tuple)
+		var elems []tftypes.Value
+		for i, e := range v.([]any) {
+			ev, err := toValue(t.ElementTypes[i], e)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems = append(elems, ev)
+		}
+		return tftypes.NewValue(typ, elems), nil
+	case typ.Is(tftypes.Map{}):
+		elems := make(map[string]tftypes.Value)
+		for k, e := range v.(map[string]any) {
+			ev, err := toValue(typ.(tftypes.Map).ElementType, e)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems[k] = ev
+		}
+		return tftypes.NewValue(typ, elems), nil
+	case typ.Is(tftypes.Object{}):
+		o := typ.(tftypes.Object)
+		attrs := make(map[string]tftypes.Value)
+		m := v.(map[string]any)
+		for k := range m {
+			if _, ok := o.AttributeTypes[k]; !ok {
+				return tftypes.Value{}, fmt.Errorf("unknown attribute %q", k)
+			}
+		}
+		for k, at := range o.AttributeTypes {
+			av, err := toValue(at, m[k])
+			if err != nil {
+				return tftypes.Value{}, fmt.Errorf("%s: %w", k, err)
+			}
+			attrs[k] = av
+		}
+		return tftypes.NewValue(typ, attrs), nil
+	}
+	return tftypes.Value{}, fmt.Errorf("unsupported type %s", typ)
+}
+
+// inferType returns the type Terraform gives a literal value of a dynamic
+// attribute.
+func inferType(v any) tftypes.Type {
+	switch v := v.(type) {
+	case string:
+		return tftypes.String
+	case bool:
+		return tftypes.Bool
+	case []any:
+		var elems []tftypes.Type
+		for _, e := range v {
+			elems = append(elems, inferType(e))
+		}
+		return tftypes.Tuple{ElementTypes: elems}
+	case map[string]any:
+		attrs := make(map[string]tftypes.Type)
+		for k, e := range v {
+			attrs[k] = inferType(e)
+		}
+		return tftypes.Object{AttributeTypes: attrs}
+	default:
+		return tftypes.Number
+	}
+}
+
+// attrValue returns the Go value at a dotted path, such as "pricing_bands.0.id",
+// of a value: a string, float64, bool, nil when null, or unknown.
+func attrValue(t *testing.T, v tftypes.Value, path string) any {
+	t.Helper()
+
+	for _, step := range strings.Split(path, ".") {
+		if v.IsNull() {
+			return nil
+		}
+		if !v.IsKnown() {
+			return unknown
+		}
+		switch {
+		case v.Type().Is(tftypes.Object{}), v.Type().Is(tftypes.Map{}):
+			var m map[string]tftypes.Value
+			if err := v.As(&m); err != nil {
+				t.Fatal(err)
+			}
+			next, ok := m[step]
+			if !ok {
+				t.Fatalf("no attribute %q in %s", step, path)
+			}
+			v = next
+		default:
+			var l []tftypes.Value
+			if err := v.As(&l); err != nil {
+				t.Fatal(err)
+			}
+			i, err := strconv.Atoi(step)
+			if err != nil || i >= len(l) {
+				t.Fatalf("no element %q in %s", step, path)
+			}
+			v = l[i]
+		}
+	}
+
+	if v.IsNull() {
+		return nil
+	}
+	if !v.IsKnown() {
+		return unknown
+	}
+	switch {
+	case v.Type().Is(tftypes.String):
+		var s string
+		_ = v.As(&s)
+		return s
+	case v.Type().Is(tftypes.Bool):
+		var b bool
+		_ = v.As(&b)
+		return b
+	case v.Type().Is(tftypes.Number):
+		var f big.Float
+		_ = v.As(&f)
+		n, _ := f.Float64()
+		return n
+	default:
+		var l []tftypes.Value
+		if err := v.As(&l); err == nil {
+			return len(l)
+		}
+		var m map[string]tftypes.Value
+		if err := v.As(&m); err == nil {
+			return len(m)
+		}
+	}
+	t.Fatalf("unsupported value at %s", path)
+	return nil
+}
+
+// proposedNewState merges the prior state into the configuration like
+// Terraform does before planning: computed attributes which aren't
+// configured keep their prior value.
+func proposedNewState(attrs []*tfprotov6.SchemaAttribute, prior, config tftypes.Value) tftypes.Value {
+	if config.IsNull() || !config.IsKnown() {
+		return config
+	}
+
+	var priorAttrs, configAttrs map[string]tftypes.Value
+	if !prior.IsNull() && prior.IsKnown() {
+		_ = prior.As(&priorAttrs)
+	}
+	_ = config.As(&configAttrs)
+
+	values := make(map[string]tftypes.Value)
+	for _, a := range attrs {
+		cv := configAttrs[a.Name]
+		pv, hasPrior := priorAttrs[a.Name]
+		if !hasPrior {
+			pv = tftypes.NewValue(cv.Type(), nil)
+		}
+
+		switch {
+		case cv.IsNull() && a.Computed:
+			values[a.Name] = pv
+		case a.NestedType != nil && cv.IsKnown() && !pv.IsNull() && pv.IsKnown():
+			values[a.Name] = proposedNewNested(a.NestedType, pv, cv)
+		default:
+			values[a.Name] = cv
+		}
+	}
+	// Blocks, such as timeouts, have no computed attributes
+	for name, cv := range configAttrs {
+		if _, ok := values[name]; !ok {
+			values[name] = cv
+		}
+	}
+	return tftypes.NewValue(config.Type(), values)
+}
+
+func proposedNewNested(nested *tfprotov6.SchemaObject, prior, config tftypes.Value) tftypes.Value {
+	switch nested.Nesting {
+	case tfprotov6.SchemaObjectNestingModeSingle:
+		return proposedNewState(nested.Attributes, prior, config)
+	case tfprotov6.SchemaObjectNestingModeList:
+		var priorElems, configElems []tftypes.Value
+		_ = prior.As(&priorElems)
+		_ = config.As(&configElems)
+		var elems []tftypes.Value
+		for i, cv := range configElems {
+			pv := tftypes.NewValue(cv.Type(), nil)
+			if i < len(priorElems) {
+				pv = priorElems[i]
+			}
+			elems = append(elems, proposedNewState(nested.Attributes, pv, cv))
+		}
+		return tftypes.NewValue(config.Type(), elems)
+	}
+	return config
+}
+
+// assertPlanValid checks that the planned value of every attribute which
+// isn't computed is its configured value, like Terraform does.
+func assertPlanValid(path *tftypes.AttributePath, attrs []*tfprotov6.SchemaAttribute, config, planned tftypes.Value) error {
+	if config.IsNull() || !config.IsKnown() || planned.IsNull() || !planned.IsKnown() {
+		return nil
+	}
+
+	var configAttrs, plannedAttrs map[string]tftypes.Value
+	_ = config.As(&configAttrs)
+	_ = planned.As(&plannedAttrs)
+	for _, a := range attrs {
+		attrPath := path.WithAttributeName(a.Name)
+		cv, pv := configAttrs[a.Name], plannedAttrs[a.Name]
+		if a.Computed {
+			continue
+		}
+		if a.NestedType != nil && a.NestedType.Nesting == tfprotov6.SchemaObjectNestingModeSingle {
+			if err := assertPlanValid(attrPath, a.NestedType.Attributes, cv, pv); err != nil {
+				return err
+			}
+			continue
+		}
+		if a.NestedType != nil && a.NestedType.Nesting == tfprotov6.SchemaObjectNestingModeList && cv.IsKnown() && pv.IsKnown() && !cv.IsNull() && !pv.IsNull() {
+			var configElems, plannedElems []tftypes.Value
+			_ = cv.As(&configElems)
+			_ = pv.As(&plannedElems)
+			if len(configElems) != len(plannedElems) {
+				return fmt.Errorf("%s: planned %d elements, configured %d", attrPath, len(plannedElems), len(configElems))
+			}
+			for i := range configElems {
+				if err := assertPlanValid(attrPath.WithElementKeyInt(i), a.NestedType.Attributes, configElems[i], plannedElems[i]); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if a.NestedType != nil {
+			continue
+		}
+		if !cv.Equal(pv) {
+			return fmt.Errorf("%s: planned value %s for a non-computed attribute, configured %s", attrPath, pv, cv)
+		}
+	}
+	return nil
+}
+
+// assertCompatible checks that every known planned value was kept by the
+// apply, like Terraform does.
+func assertCompatible(path *tftypes.AttributePath, planned, actual tftypes.Value) error {
+	if !planned.IsKnown() {
+		return nil
+	}
+	if planned.IsFullyKnown() || planned.IsNull() || actual.IsNull() || !actual.IsKnown() {
+		if !planned.Equal(actual) {
+			return fmt.Errorf("%s: planned %s, got %s", path, planned, actual)
+		}
+		return nil
+	}
+
+	var plannedAttrs, actualAttrs map[string]tftypes.Value
+	if planned.As(&plannedAttrs) == nil && actual.As(&actualAttrs) == nil {
+		for k, pv := range plannedAttrs {
+			if err := assertCompatible(path.WithAttributeName(k), pv, actualAttrs[k]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var plannedElems, actualElems []tftypes.Value
+	if planned.As(&plannedElems) == nil && actual.As(&actualElems) == nil {
+		if planned.Type().Is(tftypes.Set{}) {
+			return nil
+		}
+		if len(plannedElems) != len(actualElems) {
+			return fmt.Errorf("%s: planned %d elements, got %d", path, len(plannedElems), len(actualElems))
+		}
+		for i := range plannedElems {
+			if err := assertCompatible(path.WithElementKeyInt(i), plannedElems[i], actualElems[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func hasErrors(diags []*tfprotov6.Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// diagnosticsSummary joins the summaries and details of diagnostics, for
+// matching expected diagnostics.
+func diagnosticsSummary(diags []*tfprotov6.Diagnostic) string {
+	var lines []string
+	for _, d := range diags {
+		lines = append(lines, fmt.Sprintf("%s: %s: %s", d.Severity, d.Summary, d.Detail))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (p *testProvider) checkDiagnostics(step string, diags []*tfprotov6.Diagnostic) {
+	p.t.Helper()
+
+	if hasErrors(diags) {
+		p.t.Fatalf("%s: %s", step, diagnosticsSummary(diags))
+	}
+}
+
+func TestProviderUserAgent(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	p.create("m3ter_product", map[string]any{
+		"name":          "Product",
+		"code":          "product",
+		"custom_fields": map[string]any{},
+	})
+	want := "terraform-provider-m3ter/test (+https://github.com/housecanary/terraform-provider-m3ter)"
+	posts := api.requestsTo(http.MethodPost, "/products")
+	if len(posts) == 0 {
+		t.Fatal("the product was not created")
+	}
+	for _, req := range posts {
+		if got := req.Header.Get("User-Agent"); got != want {
+			t.Errorf("User-Agent = %q, want %q", got, want)
+		}
+	}
+}
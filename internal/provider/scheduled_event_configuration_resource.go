@@ -5,11 +5,10 @@ package provider
 
 import (
 	"context"
-	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -23,32 +22,38 @@ var _ resource.Resource = &ScheduledEventConfigurationResource{}
 var _ resource.ResourceWithImportState = &ScheduledEventConfigurationResource{}
 
 func NewScheduledEventConfigurationResource() resource.Resource {
-	return &ScheduledEventConfigurationResource{}
+	r := &ScheduledEventConfigurationResource{}
+	r.genericResource = genericResource[ScheduledEventConfigurationResourceModel, *ScheduledEventConfigurationResourceModel]{
+		typeName:     "scheduled_event_configuration",
+		path:         "/scheduledevents/configurations",
+		name:         "scheduled event configuration",
+		importFields: []string{"name"},
+		read:         r.read,
+		write:        r.write,
+	}
+	return r
 }
 
 // ScheduledEventConfigurationResource defines the resource implementation.
 type ScheduledEventConfigurationResource struct {
-	client *m3terClient
+	genericResource[ScheduledEventConfigurationResourceModel, *ScheduledEventConfigurationResourceModel]
 }
 
 // ScheduledEventConfigurationResourceModel describes the resource data model.
 type ScheduledEventConfigurationResourceModel struct {
-	Name    types.String `tfsdk:"name"`
-	Entity  types.String `tfsdk:"entity"`
-	Field   types.String `tfsdk:"field"`
-	Offset  types.Int32  `tfsdk:"offset"`
-	Id      types.String `tfsdk:"id"`
-	Version types.Int64  `tfsdk:"version"`
+	Name     types.String   `tfsdk:"name"`
+	Entity   types.String   `tfsdk:"entity"`
+	Field    types.String   `tfsdk:"field"`
+	Offset   types.Int32    `tfsdk:"offset"`
+	Id       types.String   `tfsdk:"id"`
+	Version  types.Int64    `tfsdk:"version"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *ScheduledEventConfigurationResourceModel) GetId() types.String {
 	return r.Id
 }
 
-func (r *ScheduledEventConfigurationResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
-	resp.TypeName = req.ProviderTypeName + "_scheduled_event_configuration"
-}
-
 func (r *ScheduledEventConfigurationResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Scheduled event configuration resource",
@@ -85,49 +90,12 @@ func (r *ScheduledEventConfigurationResource) Schema(ctx context.Context, req re
 				MarkdownDescription: "Scheduled Event Configuration version",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
-func (r *ScheduledEventConfigurationResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
-	// Prevent panic if the provider has not been configured.
-	if req.ProviderData == nil {
-		return
-	}
-
-	client, ok := req.ProviderData.(*m3terClient)
-
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *m3terClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
-		)
-
-		return
-	}
-
-	r.client = client
-}
-
-func (r *ScheduledEventConfigurationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	genericCreate(ctx, req, resp, r.client, "/scheduledevents/configurations", "scheduled event configuration", r.read, r.write)
-}
-
-func (r *ScheduledEventConfigurationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	genericRead(ctx, req, resp, r.client, "/scheduledevents/configurations", "scheduled event configuration", r.read)
-}
-
-func (r *ScheduledEventConfigurationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	genericUpdate(ctx, req, resp, r.client, "/scheduledevents/configurations", "scheduled event configuration", r.read, r.write)
-}
-
-func (r *ScheduledEventConfigurationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	genericDelete[ScheduledEventConfigurationResourceModel](ctx, req, resp, r.client, "/scheduledevents/configurations", "scheduled event configuration")
-}
-
-func (r *ScheduledEventConfigurationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
-}
-
 func (r *ScheduledEventConfigurationResource) read(ctx context.Context, data *ScheduledEventConfigurationResourceModel, restModel map[string]any, diagnostics *diag.Diagnostics) {
 	m := &mapper{
 		ctx:         ctx,
@@ -0,0 +1,230 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ServiceUserResource{}
+var _ resource.ResourceWithImportState = &ServiceUserResource{}
+
+func NewServiceUserResource() resource.Resource {
+	r := &ServiceUserResource{}
+	r.genericResource = genericResource[ServiceUserResourceModel, *ServiceUserResourceModel]{
+		typeName:     "service_user",
+		path:         "/serviceusers",
+		name:         "service user",
+		importFields: []string{"name"},
+		read:         r.read,
+		write:        r.write,
+	}
+	return r
+}
+
+// ServiceUserResource defines the resource implementation.
+type ServiceUserResource struct {
+	genericResource[ServiceUserResourceModel, *ServiceUserResourceModel]
+}
+
+// ServiceUserResourceModel describes the resource data model.
+type ServiceUserResourceModel struct {
+	Name                types.String   `tfsdk:"name"`
+	PermissionPolicyIds types.Set      `tfsdk:"permission_policy_ids"`
+	Id                  types.String   `tfsdk:"id"`
+	Version             types.Int64    `tfsdk:"version"`
+	Timeouts            timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *ServiceUserResourceModel) GetId() types.String {
+	return r.Id
+}
+
+func (r *ServiceUserResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "ServiceUser resource. Permission policies already attached to the service user outside Terraform, for which m3ter responds with a 409 Conflict when attaching them again, are treated as attached.",
+
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the service user.",
+				Required:            true,
+			},
+			"permission_policy_ids": schema.SetAttribute{
+				MarkdownDescription: "The UUIDs of the permission policies attached to the service user. When not set, the policies attached are read but not managed, so policies attached outside Terraform are kept.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The UUID of the entity.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"version": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The version number.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
+	}
+}
+
+func (r *ServiceUserResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	genericCreate(ctx, req, resp, r.client, r.path, r.name, r.readAndSyncPolicies, r.write)
+}
+
+func (r *ServiceUserResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	genericRead(ctx, req, resp, r.client, r.path, r.name, r.readWithPolicies)
+}
+
+func (r *ServiceUserResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	genericUpdate(ctx, req, resp, r.client, r.path, r.name, r.readAndSyncPolicies, r.write)
+}
+
+// readWithPolicies maps the service user, then reads the policies attached to
+// it. The policies are read within the operation, so its timeout bounds them.
+func (r *ServiceUserResource) readWithPolicies(ctx context.Context, data *ServiceUserResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	r.read(ctx, data, restData, diagnostics)
+	if diagnostics.HasError() {
+		return
+	}
+	r.readPolicies(ctx, data, diagnostics)
+}
+
+// readAndSyncPolicies maps the service user created or updated, then attaches
+// and detaches its policies as planned.
+func (r *ServiceUserResource) readAndSyncPolicies(ctx context.Context, data *ServiceUserResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	r.read(ctx, data, restData, diagnostics)
+	if diagnostics.HasError() {
+		return
+	}
+	r.syncPolicies(ctx, data, diagnostics)
+}
+
+// policies returns the ids of the permission policies attached to the service
+// user.
+func (r *ServiceUserResource) policies(ctx context.Context, serviceUserId string) ([]string, error) {
+	var policyIds []string
+	err := r.client.list(ctx, "/serviceusers/"+url.PathEscape(serviceUserId)+"/permissionpolicies", nil, func(restData map[string]any) bool {
+		if id, ok := restData["id"].(string); ok {
+			policyIds = append(policyIds, id)
+		}
+		return true
+	})
+	return policyIds, err
+}
+
+// syncPolicies attaches and detaches permission policies so that exactly the
+// configured policies are attached to the service user, then reads back the
+// result. Policies are left as they are when none are configured.
+func (r *ServiceUserResource) syncPolicies(ctx context.Context, data *ServiceUserResourceModel, diagnostics *diag.Diagnostics) {
+	serviceUserId := data.Id.ValueString()
+
+	if data.PermissionPolicyIds.IsUnknown() || data.PermissionPolicyIds.IsNull() {
+		r.readPolicies(ctx, data, diagnostics)
+		return
+	}
+
+	var planned []string
+	diagnostics.Append(data.PermissionPolicyIds.ElementsAs(ctx, &planned, false)...)
+	if diagnostics.HasError() {
+		return
+	}
+
+	attached, err := r.policies(ctx, serviceUserId)
+	if err != nil {
+		diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list permission policies of service user, got error: %s", err))
+		return
+	}
+
+	for _, policyId := range attached {
+		if slices.Contains(planned, policyId) {
+			continue
+		}
+		err := r.client.execute(ctx, "POST", "/serviceusers/"+url.PathEscape(serviceUserId)+"/permissionpolicies/"+url.PathEscape(policyId)+"/remove", nil, nil, nil)
+		if sc, ok := err.(*statusCodeError); ok && sc.StatusCode == 404 {
+			continue
+		}
+		if err != nil {
+			diagnostics.AddError("Client Error", fmt.Sprintf("Unable to detach permission policy %s from service user, got error: %s", policyId, err))
+			return
+		}
+	}
+
+	for _, policyId := range planned {
+		if slices.Contains(attached, policyId) {
+			continue
+		}
+		err := r.client.execute(ctx, "POST", "/serviceusers/"+url.PathEscape(serviceUserId)+"/permissionpolicies/"+url.PathEscape(policyId)+"/add", nil, nil, nil)
+		// The policy has been attached since it was listed
+		if isConflict(err) {
+			continue
+		}
+		if err != nil {
+			diagnostics.AddError("Client Error", fmt.Sprintf("Unable to attach permission policy %s to service user, got error: %s", policyId, err))
+			return
+		}
+	}
+
+	r.readPolicies(ctx, data, diagnostics)
+}
+
+func (r *ServiceUserResource) readPolicies(ctx context.Context, data *ServiceUserResourceModel, diagnostics *diag.Diagnostics) {
+	attached, err := r.policies(ctx, data.Id.ValueString())
+	if err != nil {
+		diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list permission policies of service user, got error: %s", err))
+		return
+	}
+
+	if attached == nil {
+		attached = []string{}
+	}
+	slices.Sort(attached)
+
+	sv, diag := types.SetValueFrom(ctx, types.StringType, attached)
+	diagnostics.Append(diag...)
+	data.PermissionPolicyIds = sv
+}
+
+func (r *ServiceUserResource) read(ctx context.Context, data *ServiceUserResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           restData,
+	}
+	m.to("id", &data.Id)
+	m.to("version", &data.Version)
+	m.to("name", &data.Name)
+}
+
+func (r *ServiceUserResource) write(ctx context.Context, data *ServiceUserResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           restData,
+	}
+	m.from(data.Id, "id")
+	m.from(data.Version, "version")
+	m.from(data.Name, "name")
+}
@@ -0,0 +1,123 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// handleServiceUserPolicies serves the permission policies of service users
+// from attached, keyed by service user id.
+func handleServiceUserPolicies(api *fakeAPI, attached map[string][]string) {
+	api.handle(func(w http.ResponseWriter, req *fakeRequest) bool {
+		segments := strings.Split(strings.TrimPrefix(req.Path, "/"), "/")
+		if len(segments) < 3 || segments[0] != "serviceusers" || segments[2] != "permissionpolicies" {
+			return false
+		}
+		userId := segments[1]
+		switch {
+		case len(segments) == 3 && req.Method == http.MethodGet:
+			data := []any{}
+			for _, id := range attached[userId] {
+				data = append(data, map[string]any{"id": id})
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"data": data})
+		case len(segments) == 5 && segments[4] == "add":
+			attached[userId] = append(attached[userId], segments[3])
+			writeJSON(w, http.StatusOK, map[string]any{})
+		case len(segments) == 5 && segments[4] == "remove":
+			attached[userId] = slices.DeleteFunc(attached[userId], func(id string) bool { return id == segments[3] })
+			writeJSON(w, http.StatusOK, map[string]any{})
+		default:
+			return false
+		}
+		return true
+	})
+}
+
+func TestServiceUserResourceUnmanagedPolicies(t *testing.T) {
+	api := newFakeAPI(t)
+	attached := make(map[string][]string)
+	handleServiceUserPolicies(api, attached)
+	p := newTestProvider(t, api, nil)
+
+	// Without permission_policy_ids, the policies attached are only read
+	config := map[string]any{"name": "ci"}
+	state := p.create("m3ter_service_user", config)
+	id := attrValue(t, state, "id").(string)
+	if v := attrValue(t, state, "permission_policy_ids"); v != 0 {
+		t.Errorf("permission_policy_ids has %v policies, want none", v)
+	}
+
+	attached[id] = []string{"00000000-0000-4000-8000-0000000000p1"}
+	state = p.read("m3ter_service_user", state)
+	p.assertNoChanges("m3ter_service_user", state, config)
+	config["name"] = "Renamed"
+	p.update("m3ter_service_user", state, config)
+	if len(attached[id]) != 1 {
+		t.Errorf("attached policies %v after an update, want the policy attached outside Terraform kept", attached[id])
+	}
+}
+
+func TestServiceUserResourceManagedPolicies(t *testing.T) {
+	api := newFakeAPI(t)
+	attached := make(map[string][]string)
+	handleServiceUserPolicies(api, attached)
+	p := newTestProvider(t, api, nil)
+
+	config := map[string]any{
+		"name":                  "ci",
+		"permission_policy_ids": []any{"00000000-0000-4000-8000-0000000000p1"},
+	}
+	state := p.create("m3ter_service_user", config)
+	id := attrValue(t, state, "id").(string)
+	if !slices.Equal(attached[id], []string{"00000000-0000-4000-8000-0000000000p1"}) {
+		t.Errorf("attached policies %v, want the configured policy", attached[id])
+	}
+
+	// A policy attached outside Terraform is detached when policies are managed
+	attached[id] = append(attached[id], "00000000-0000-4000-8000-0000000000p2")
+	state = p.read("m3ter_service_user", state)
+	planned, diags := p.plan("m3ter_service_user", state, config)
+	p.checkDiagnostics("plan", diags)
+	state, diags = p.apply("m3ter_service_user", state, planned, config)
+	p.checkDiagnostics("apply", diags)
+	if !slices.Equal(attached[id], []string{"00000000-0000-4000-8000-0000000000p1"}) {
+		t.Errorf("attached policies %v, want only the configured policy", attached[id])
+	}
+	p.assertNoChanges("m3ter_service_user", state, config)
+
+	config["permission_policy_ids"] = []any{}
+	p.update("m3ter_service_user", state, config)
+	if len(attached[id]) != 0 {
+		t.Errorf("attached policies %v, want none", attached[id])
+	}
+}
+
+func TestServiceUserResourceImportAmbiguousName(t *testing.T) {
+	api := newFakeAPI(t)
+	handleServiceUserPolicies(api, make(map[string][]string))
+	p := newTestProvider(t, api, nil)
+	firstId := api.put("serviceusers", map[string]any{"name": "ci"})
+	api.put("serviceusers", map[string]any{"name": "ci"})
+	otherId := api.put("serviceusers", map[string]any{"name": "deploy"})
+
+	// A name shared by several service users doesn't pick one of them
+	_, diags := p.tryImportState("m3ter_service_user", "ci")
+	if summary := diagnosticsSummary(diags); !hasErrors(diags) || !strings.Contains(summary, "Multiple matching service users") || !strings.Contains(summary, firstId) {
+		t.Errorf("got diagnostics %q, want the service users named ci reported", summary)
+	}
+
+	state := p.importState("m3ter_service_user", "deploy")
+	if v := attrValue(t, state, "id"); v != otherId {
+		t.Errorf("id = %v, want %s", v, otherId)
+	}
+	state = p.importState("m3ter_service_user", firstId)
+	if v := attrValue(t, state, "id"); v != firstId {
+		t.Errorf("id = %v, want %s", v, firstId)
+	}
+}
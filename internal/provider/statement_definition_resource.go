@@ -0,0 +1,281 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &StatementDefinitionResource{}
+var _ resource.ResourceWithImportState = &StatementDefinitionResource{}
+
+func NewStatementDefinitionResource() resource.Resource {
+	r := &StatementDefinitionResource{}
+	r.genericResource = genericResource[StatementDefinitionResourceModel, *StatementDefinitionResourceModel]{
+		typeName: "statement_definition",
+		path:     "/statementdefinitions",
+		name:     "statement definition",
+		read:     r.read,
+		write:    r.write,
+	}
+	return r
+}
+
+// StatementDefinitionResource defines the resource implementation.
+type StatementDefinitionResource struct {
+	genericResource[StatementDefinitionResourceModel, *StatementDefinitionResourceModel]
+}
+
+// StatementDefinitionResourceModel describes the resource data model.
+type StatementDefinitionResourceModel struct {
+	Name                 types.String   `tfsdk:"name"`
+	IncludePricingType   types.String   `tfsdk:"include_pricing_type"`
+	AggregationFrequency types.String   `tfsdk:"aggregation_frequency"`
+	Dimensions           types.List     `tfsdk:"dimensions"`
+	Measures             types.List     `tfsdk:"measures"`
+	Id                   types.String   `tfsdk:"id"`
+	Version              types.Int64    `tfsdk:"version"`
+	Timeouts             timeouts.Value `tfsdk:"timeouts"`
+}
+
+var statementDefinitionDimensionType = schema.NestedAttributeObject{
+	Attributes: map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			MarkdownDescription: "The name of the Dimension.",
+			Required:            true,
+		},
+		"meter_id": schema.StringAttribute{
+			MarkdownDescription: "The UUID of the Meter the Dimension is taken from.",
+			Required:            true,
+		},
+		"attributes": schema.ListAttribute{
+			MarkdownDescription: "The Meter fields the usage is broken down by.",
+			ElementType:         types.StringType,
+			Optional:            true,
+		},
+		"filter": schema.ListAttribute{
+			MarkdownDescription: "The values of the Dimension to include in the Statement.",
+			ElementType:         types.StringType,
+			Required:            true,
+		},
+	},
+}
+
+// statementDefinitionDimensionFields maps the dimension attribute names to the
+// API field names.
+var statementDefinitionDimensionFields = map[string]string{
+	"name":       "name",
+	"meter_id":   "meterId",
+	"attributes": "attributes",
+	"filter":     "filter",
+}
+
+var statementDefinitionMeasureType = schema.NestedAttributeObject{
+	Attributes: map[string]schema.Attribute{
+		"meter_id": schema.StringAttribute{
+			MarkdownDescription: "The UUID of the Meter the Measure is taken from.",
+			Required:            true,
+		},
+		"aggregations": schema.ListAttribute{
+			MarkdownDescription: "The aggregations applied to the Measure, such as SUM, MIN, MAX, COUNT, LATEST, MEAN or UNIQUE.",
+			ElementType:         types.StringType,
+			Required:            true,
+		},
+	},
+}
+
+// statementDefinitionMeasureFields maps the measure attribute names to the API
+// field names.
+var statementDefinitionMeasureFields = map[string]string{
+	"meter_id":     "meterId",
+	"aggregations": "aggregations",
+}
+
+func (r *StatementDefinitionResourceModel) GetId() types.String {
+	return r.Id
+}
+
+func (r *StatementDefinitionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "StatementDefinition resource",
+
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Descriptive name for the Statement Definition.",
+				Required:            true,
+			},
+			"include_pricing_type": schema.StringAttribute{
+				MarkdownDescription: "Whether the Statement includes the price of the usage. One of INCLUDED, NOT_INCLUDED or BOTH.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("INCLUDED", "NOT_INCLUDED", "BOTH"),
+				},
+			},
+			"aggregation_frequency": schema.StringAttribute{
+				MarkdownDescription: "The frequency the usage is aggregated at on the Statement. One of ORIGINAL, HOUR, DAY, WEEK, MONTH, QUARTER, YEAR or WHOLE_PERIOD.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("ORIGINAL", "HOUR", "DAY", "WEEK", "MONTH", "QUARTER", "YEAR", "WHOLE_PERIOD"),
+				},
+			},
+			"dimensions": schema.ListNestedAttribute{
+				MarkdownDescription: "The Dimensions the usage on the Statement is broken down by.",
+				Optional:            true,
+				NestedObject:        statementDefinitionDimensionType,
+			},
+			"measures": schema.ListNestedAttribute{
+				MarkdownDescription: "The Measures shown on the Statement.",
+				Optional:            true,
+				NestedObject:        statementDefinitionMeasureType,
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The UUID of the entity.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"version": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The version number.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
+	}
+}
+
+func (r *StatementDefinitionResource) read(ctx context.Context, data *StatementDefinitionResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           restData,
+	}
+	m.to("id", &data.Id)
+	m.to("version", &data.Version)
+	m.to("name", &data.Name)
+	m.to("includePricingType", &data.IncludePricingType)
+	m.to("aggregationFrequency", &data.AggregationFrequency)
+
+	m.listTo("dimensions", &data.Dimensions, statementDefinitionDimensionType.Type(), func(v any) (attr.Value, diag.Diagnostics) {
+		return readStatementDefinitionObject(v, statementDefinitionDimensionType, statementDefinitionDimensionFields)
+	})
+	m.listTo("measures", &data.Measures, statementDefinitionMeasureType.Type(), func(v any) (attr.Value, diag.Diagnostics) {
+		return readStatementDefinitionObject(v, statementDefinitionMeasureType, statementDefinitionMeasureFields)
+	})
+}
+
+// readStatementDefinitionObject maps a dimension or measure of the API entity
+// into an object of the given type, whose attributes are strings or lists of
+// strings.
+func readStatementDefinitionObject(v any, objectType schema.NestedAttributeObject, fields map[string]string) (attr.Value, diag.Diagnostics) {
+	mv, ok := v.(map[string]any)
+	if !ok {
+		return nil, diag.Diagnostics{diag.NewErrorDiagnostic("cannot map list element, expected map", "")}
+	}
+
+	var diags diag.Diagnostics
+	attrs := make(map[string]attr.Value)
+	ts := make(map[string]attr.Type)
+	for k, field := range fields {
+		ts[k] = objectType.Attributes[k].GetType()
+		if ts[k].Equal(types.StringType) {
+			if s, ok := mv[field].(string); ok {
+				attrs[k] = types.StringValue(s)
+			} else {
+				attrs[k] = types.StringNull()
+			}
+			continue
+		}
+
+		// An empty optional list reads as not set
+		list, ok := mv[field].([]any)
+		if !ok || (len(list) == 0 && objectType.Attributes[k].IsOptional()) {
+			attrs[k] = types.ListNull(types.StringType)
+			continue
+		}
+		elements := make([]attr.Value, 0, len(list))
+		for _, e := range list {
+			if s, ok := e.(string); ok {
+				elements = append(elements, types.StringValue(s))
+			} else {
+				diags.AddError("cannot map list element, expected string", "")
+			}
+		}
+		lv, diag := types.ListValue(types.StringType, elements)
+		diags.Append(diag...)
+		attrs[k] = lv
+	}
+
+	ov, diag := types.ObjectValue(ts, attrs)
+	diags.Append(diag...)
+	return ov, diags
+}
+
+func (r *StatementDefinitionResource) write(ctx context.Context, data *StatementDefinitionResourceModel, restData map[string]any, diagnostics *diag.Diagnostics) {
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: diagnostics,
+		v:           restData,
+	}
+	m.from(data.Id, "id")
+	m.from(data.Version, "version")
+	m.from(data.Name, "name")
+	m.from(data.IncludePricingType, "includePricingType")
+	m.from(data.AggregationFrequency, "aggregationFrequency")
+
+	m.listFrom(data.Dimensions, "dimensions", func(v attr.Value) (any, diag.Diagnostics) {
+		return writeStatementDefinitionObject(ctx, v, statementDefinitionDimensionFields)
+	})
+	m.listFrom(data.Measures, "measures", func(v attr.Value) (any, diag.Diagnostics) {
+		return writeStatementDefinitionObject(ctx, v, statementDefinitionMeasureFields)
+	})
+}
+
+// writeStatementDefinitionObject maps a dimension or measure object into the
+// API entity.
+func writeStatementDefinitionObject(ctx context.Context, v attr.Value, fields map[string]string) (any, diag.Diagnostics) {
+	ov, ok := v.(types.Object)
+	if !ok {
+		return nil, diag.Diagnostics{diag.NewErrorDiagnostic("cannot map list element, expected object", "")}
+	}
+
+	var diags diag.Diagnostics
+	restData := make(map[string]any)
+	m := &mapper{
+		ctx:         ctx,
+		diagnostics: &diags,
+		v:           restData,
+	}
+	attrs := ov.Attributes()
+	for k, field := range fields {
+		switch v := attrs[k].(type) {
+		case types.List:
+			m.listFrom(v, field, func(v attr.Value) (any, diag.Diagnostics) {
+				if sv, ok := v.(types.String); ok {
+					return sv.ValueString(), nil
+				}
+
+				return nil, diag.Diagnostics{diag.NewErrorDiagnostic("cannot map list element, expected string", "")}
+			})
+		case unknowable:
+			m.from(v, field)
+		}
+	}
+
+	return restData, diags
+}
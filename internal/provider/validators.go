@@ -0,0 +1,366 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ planmodifier.String = productIdModifier{}
+var _ validator.Number = exactNumberValidator{}
+var _ validator.Number = numberAtLeastValidator{}
+var _ validator.String = httpsURLValidator{}
+var _ validator.String = proxyURLValidator{}
+var _ validator.String = dateValidator{}
+var _ validator.String = iso8601Validator{}
+var _ resource.ConfigValidator = minimumSpendDescriptionValidator{}
+var _ resource.ConfigValidator = tiersSpanPlanValidator{}
+
+// dateValidator validates that a string attribute is a real calendar date in
+// the YYYY-MM-DD format, so dates such as 2023-02-30 are rejected.
+type dateValidator struct{}
+
+func validDate() validator.String {
+	return dateValidator{}
+}
+
+func (v dateValidator) Description(ctx context.Context) string {
+	return "value must be a valid date in the format YYYY-MM-DD"
+}
+
+func (v dateValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v dateValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := time.Parse(time.DateOnly, req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid date",
+			fmt.Sprintf("The value must be a valid date in the format YYYY-MM-DD, got %q.", req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+// iso8601Validator validates that a string attribute is an ISO-8601 date, in
+// the YYYY-MM-DD format, or an RFC 3339 datetime.
+type iso8601Validator struct{}
+
+func iso8601() validator.String {
+	return iso8601Validator{}
+}
+
+func (v iso8601Validator) Description(ctx context.Context) string {
+	return "value must be a date in the format YYYY-MM-DD or an RFC 3339 datetime"
+}
+
+func (v iso8601Validator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v iso8601Validator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if _, err := time.Parse(time.DateOnly, value); err == nil {
+		return
+	}
+	if _, err := time.Parse(time.RFC3339, value); err == nil {
+		return
+	}
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid ISO-8601 date",
+		fmt.Sprintf("The value must be a date in the format YYYY-MM-DD or an RFC 3339 datetime such as 2024-01-01T00:00:00Z, got %q.", value),
+	)
+}
+
+// productIdModifier plans the product_id of a resource which may instead be
+// given a product_code. Without either, the entity is global and the plan is
+// null, and an unchanged product_code keeps the product resolved from it.
+type productIdModifier struct{}
+
+func productIdFromCode() planmodifier.String {
+	return productIdModifier{}
+}
+
+func (m productIdModifier) Description(ctx context.Context) string {
+	return "the product is null unless set, or resolved from product_code"
+}
+
+func (m productIdModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m productIdModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if !req.ConfigValue.IsNull() {
+		return
+	}
+
+	var productCode types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("product_code"), &productCode)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if productCode.IsNull() {
+		resp.PlanValue = types.StringNull()
+		return
+	}
+
+	// Nothing to keep on create
+	if req.State.Raw.IsNull() {
+		return
+	}
+	var priorProductCode types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("product_code"), &priorProductCode)...)
+	if productCode.Equal(priorProductCode) && !req.StateValue.IsNull() {
+		resp.PlanValue = req.StateValue
+	}
+}
+
+// exactNumberValidator validates that an integer number attribute can be
+// represented exactly as a float64, as the API stores numbers as doubles and
+// larger integers would be silently rounded.
+type exactNumberValidator struct{}
+
+func exactNumber() validator.Number {
+	return exactNumberValidator{}
+}
+
+func (v exactNumberValidator) Description(ctx context.Context) string {
+	return "integer values must be preserved exactly as a 64-bit floating point number"
+}
+
+func (v exactNumberValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v exactNumberValidator) ValidateNumber(ctx context.Context, req validator.NumberRequest, resp *validator.NumberResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	// Fractional values are rounded to the nearest float64 like any decimal
+	value := req.ConfigValue.ValueBigFloat()
+	if !value.IsInt() {
+		return
+	}
+	if _, accuracy := value.Float64(); accuracy != big.Exact {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Value out of exact range",
+			fmt.Sprintf("The value %s cannot be represented exactly and would lose precision.", value.Text('f', -1)),
+		)
+	}
+}
+
+// numberAtLeastValidator validates that a number attribute is at least min.
+type numberAtLeastValidator struct {
+	min *big.Float
+}
+
+func numberAtLeast(min float64) validator.Number {
+	return numberAtLeastValidator{min: big.NewFloat(min)}
+}
+
+func (v numberAtLeastValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("value must be at least %s", v.min.Text('g', -1))
+}
+
+func (v numberAtLeastValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v numberAtLeastValidator) ValidateNumber(ctx context.Context, req validator.NumberRequest, resp *validator.NumberResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if req.ConfigValue.ValueBigFloat().Cmp(v.min) < 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got: %s", req.Path, v.Description(ctx), req.ConfigValue.ValueBigFloat().Text('g', -1)),
+		)
+	}
+}
+
+// checkHTTPSURL returns an error unless s is an absolute https URL, such as
+// an API base URL.
+func checkHTTPSURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("%q is not an absolute https URL", s)
+	}
+	return nil
+}
+
+// httpsURLValidator validates that a string attribute is an absolute https URL.
+type httpsURLValidator struct{}
+
+func httpsURL() validator.String {
+	return httpsURLValidator{}
+}
+
+func (v httpsURLValidator) Description(ctx context.Context) string {
+	return "value must be an absolute https URL"
+}
+
+func (v httpsURLValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v httpsURLValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := checkHTTPSURL(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid URL",
+			"The value must be an absolute https URL, got error: "+err.Error(),
+		)
+	}
+}
+
+// parseProxyURL parses s as the URL of a proxy supported by the HTTP
+// transport, with an http, https or socks5 scheme.
+func parseProxyURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("%q is not an http, https or socks5 URL", u.Redacted())
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("%q has no host", u.Redacted())
+	}
+	return u, nil
+}
+
+// proxyURLValidator validates that a string attribute is the URL of a
+// supported proxy.
+type proxyURLValidator struct{}
+
+func proxyURL() validator.String {
+	return proxyURLValidator{}
+}
+
+func (v proxyURLValidator) Description(ctx context.Context) string {
+	return "value must be an absolute http, https or socks5 URL"
+}
+
+func (v proxyURLValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v proxyURLValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := parseProxyURL(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Proxy URL",
+			"The value must be an absolute http, https or socks5 URL, got error: "+err.Error(),
+		)
+	}
+}
+
+// minimumSpendDescriptionValidator warns when a minimum spend is set without
+// the description shown on its Bill line item, which is confusing on Bills.
+type minimumSpendDescriptionValidator struct{}
+
+func minimumSpendDescription() resource.ConfigValidator {
+	return minimumSpendDescriptionValidator{}
+}
+
+func (v minimumSpendDescriptionValidator) Description(ctx context.Context) string {
+	return "minimum_spend_description should be set when minimum_spend is set"
+}
+
+func (v minimumSpendDescriptionValidator) MarkdownDescription(ctx context.Context) string {
+	return "`minimum_spend_description` should be set when `minimum_spend` is set"
+}
+
+func (v minimumSpendDescriptionValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var minimumSpend types.Float64
+	var description types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("minimum_spend"), &minimumSpend)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("minimum_spend_description"), &description)...)
+	if resp.Diagnostics.HasError() || minimumSpend.IsNull() || minimumSpend.IsUnknown() || description.IsUnknown() {
+		return
+	}
+
+	if description.ValueString() == "" {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("minimum_spend_description"),
+			"Minimum spend without description",
+			"minimum_spend is set without a minimum_spend_description, so its line item on Bills will have no description.",
+		)
+	}
+}
+
+// tiersSpanPlanValidator warns about pricing bands spanning the whole plan
+// with non-cumulative charges. Non-cumulative charges price all usage at the
+// rate of the highest band reached, which may reprice the usage of billing
+// periods already billed once usage spanning the plan reaches a higher band.
+// m3ter does not document the combination as invalid, so it is not rejected.
+type tiersSpanPlanValidator struct{}
+
+func tiersSpanPlan() resource.ConfigValidator {
+	return tiersSpanPlanValidator{}
+}
+
+func (v tiersSpanPlanValidator) Description(ctx context.Context) string {
+	return "cumulative should be true when tiers_span_plan is true"
+}
+
+func (v tiersSpanPlanValidator) MarkdownDescription(ctx context.Context) string {
+	return "`cumulative` should be true when `tiers_span_plan` is true"
+}
+
+func (v tiersSpanPlanValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var tiersSpanPlan, cumulative types.Bool
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("tiers_span_plan"), &tiersSpanPlan)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("cumulative"), &cumulative)...)
+	if resp.Diagnostics.HasError() || tiersSpanPlan.IsUnknown() || cumulative.IsUnknown() {
+		return
+	}
+
+	// Both default to false when not set
+	if tiersSpanPlan.ValueBool() && !cumulative.ValueBool() {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("cumulative"),
+			"Non-cumulative pricing spanning the plan",
+			"tiers_span_plan is true, so usage accumulates across billing periods, but cumulative is false. "+
+				"Non-cumulative pricing charges all usage at the rate of the highest band reached, which may reprice usage already billed in earlier billing periods. "+
+				"Set cumulative to true, or tiers_span_plan to false for bands which reset every billing period.",
+		)
+	}
+}
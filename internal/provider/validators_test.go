@@ -0,0 +1,51 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestISO8601Validator(t *testing.T) {
+	tests := []struct {
+		value string
+		valid bool
+	}{
+		{"2024-01-31", true},
+		{"2024-02-29", true},
+		{"2024-01-01T00:00:00Z", true},
+		{"2024-01-01T12:30:45.123Z", true},
+		{"2024-01-01T12:30:45+05:30", true},
+		{"2023-02-29", false},
+		{"2024-13-01", false},
+		{"2024-1-1", false},
+		{"01/02/2024", false},
+		{"2024-01-01T00:00:00", false},
+		{"2024-01-01 00:00:00Z", false},
+		{"2024-01-01T25:00:00Z", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		req := validator.StringRequest{Path: path.Root("start_date"), ConfigValue: types.StringValue(tt.value)}
+		resp := &validator.StringResponse{}
+		iso8601().ValidateString(context.Background(), req, resp)
+		if valid := !resp.Diagnostics.HasError(); valid != tt.valid {
+			t.Errorf("%q: valid = %v, want %v", tt.value, valid, tt.valid)
+		}
+	}
+
+	// Null and unknown values are left to other checks
+	for _, v := range []types.String{types.StringNull(), types.StringUnknown()} {
+		resp := &validator.StringResponse{}
+		iso8601().ValidateString(context.Background(), validator.StringRequest{ConfigValue: v}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Errorf("%s: got diagnostics %v", v, resp.Diagnostics)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -34,14 +35,15 @@ type WebhookDestinationResource struct {
 
 // WebhookDestinationResourceModel describes the resource data model.
 type WebhookDestinationResourceModel struct {
-	Name        types.String `tfsdk:"name"`
-	Description types.String `tfsdk:"description"`
-	Url         types.String `tfsdk:"url"`
-	Code        types.String `tfsdk:"code"`
-	Active      types.Bool   `tfsdk:"active"`
-	Credentials types.Object `tfsdk:"credentials"`
-	Id          types.String `tfsdk:"id"`
-	Version     types.Int64  `tfsdk:"version"`
+	Name        types.String   `tfsdk:"name"`
+	Description types.String   `tfsdk:"description"`
+	Url         types.String   `tfsdk:"url"`
+	Code        types.String   `tfsdk:"code"`
+	Active      types.Bool     `tfsdk:"active"`
+	Credentials types.Object   `tfsdk:"credentials"`
+	Id          types.String   `tfsdk:"id"`
+	Version     types.Int64    `tfsdk:"version"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *WebhookDestinationResourceModel) GetId() types.String {
@@ -66,6 +68,13 @@ var credentialsAttributes = map[string]schema.Attribute{
 	},
 }
 
+// webhookCredentialsFields maps the credentials attribute names to the API
+// field names.
+var webhookCredentialsFields = map[string]string{
+	"api_key": "apiKey",
+	"secret":  "secret",
+}
+
 func (r *WebhookDestinationResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_webhook_destination"
 }
@@ -122,6 +131,9 @@ func (r *WebhookDestinationResource) Schema(ctx context.Context, req resource.Sc
 				MarkdownDescription: "Webhook Destination version",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
 	}
 }
 
@@ -179,6 +191,11 @@ func (r *WebhookDestinationResource) read(ctx context.Context, data *WebhookDest
 	m.to("url", &data.Url)
 	m.to("code", &data.Code)
 	m.to("active", &data.Active)
+	// An explicit false is kept from the plan, while an unset flag the API
+	// doesn't return reads as not set rather than staying unknown
+	if data.Active.IsUnknown() {
+		data.Active = types.BoolNull()
+	}
 
 	// Never map the credentials back to the model since they are write-only
 }
@@ -198,22 +215,12 @@ func (r *WebhookDestinationResource) write(ctx context.Context, data *WebhookDes
 	m.from(data.Code, "code")
 	m.from(data.Active, "active")
 
-	creds, ok := webhookModel["credentials"].(map[string]any)
-	if !ok {
-		creds = make(map[string]any)
-		webhookModel["credentials"] = creds
-	}
-
-	attrs := data.Credentials.Attributes()
-
-	credsM := &mapper{
-		ctx:         ctx,
-		diagnostics: diagnostics,
-		v:           creds,
+	if data.Credentials.IsNull() || data.Credentials.IsUnknown() {
+		return
 	}
 
-	credsM.from(attrs["api_key"], "apiKey")
-	credsM.from(attrs["secret"], "secret")
+	m.objectFrom(data.Credentials, "credentials", webhookCredentialsFields)
+	creds := webhookModel["credentials"].(map[string]any)
 	creds["type"] = "M3TER_SIGNED_REQUEST"
 	creds["empty"] = false
 }
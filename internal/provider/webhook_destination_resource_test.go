@@ -0,0 +1,65 @@
+// Copyright (c) HouseCanary, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+)
+
+func testWebhookDestinationConfig() map[string]any {
+	return map[string]any{
+		"name":        "Billing events",
+		"description": "Sends billing events",
+		"url":         "https://example.com/webhook",
+		"code":        "billing_events",
+		"credentials": map[string]any{"api_key": "key", "secret": "secret"},
+	}
+}
+
+func TestWebhookDestinationResourceNullCredentials(t *testing.T) {
+	api := newFakeAPI(t)
+	// The API never returns the credentials it stores
+	api.onWrite = func(collection string, entity map[string]any) {
+		if collection == "integrationdestinations/webhooks" {
+			entity["credentials"] = nil
+		}
+	}
+	p := newTestProvider(t, api, nil)
+
+	config := testWebhookDestinationConfig()
+	state := p.create("m3ter_webhook_destination", config)
+	id := attrValue(t, state, "id").(string)
+	state = p.read("m3ter_webhook_destination", state)
+	if v := attrValue(t, state, "credentials.secret"); v != "secret" {
+		t.Errorf("credentials.secret = %v, want the configured secret kept", v)
+	}
+	p.assertNoChanges("m3ter_webhook_destination", state, config)
+
+	// Rotating the secret sends whole credentials despite the null ones read
+	config["credentials"] = map[string]any{"api_key": "key", "secret": "rotated"}
+	p.update("m3ter_webhook_destination", state, config)
+	sent := api.requestsTo("PUT", "/integrationdestinations/webhooks/"+id)[0].Body
+	creds, _ := sent["credentials"].(map[string]any)
+	if creds["apiKey"] != "key" || creds["secret"] != "rotated" || creds["type"] != "M3TER_SIGNED_REQUEST" {
+		t.Errorf("sent credentials %v, want the rotated secret", creds)
+	}
+}
+
+func TestWebhookDestinationResourceInactive(t *testing.T) {
+	api := newFakeAPI(t)
+	p := newTestProvider(t, api, nil)
+
+	config := testWebhookDestinationConfig()
+	config["active"] = false
+	state := p.create("m3ter_webhook_destination", config)
+	id := attrValue(t, state, "id").(string)
+	if v, ok := api.get("integrationdestinations/webhooks", id)["active"]; !ok || v != false {
+		t.Errorf("sent active = %v, want false", v)
+	}
+	state = p.read("m3ter_webhook_destination", state)
+	if v := attrValue(t, state, "active"); v != false {
+		t.Errorf("active = %v, want false", v)
+	}
+	p.assertNoChanges("m3ter_webhook_destination", state, config)
+}